@@ -0,0 +1,140 @@
+package seedr
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// TryAttributesCustomBatch returns fields of n instances of trait with given overrides,
+// with generators and DependsOn fields evaluated. Auto() and relation fields are omitted.
+// No driver is called and no related traits are created.
+func (sdr *Seedr) TryAttributesCustomBatch(traitName string, n int, override Trait) ([]Trait, error) {
+	t, err := sdr.getPublicTrait(traitName)
+	if err != nil {
+		return nil, err
+	}
+	rt, err := t.next(n, override)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]Trait, len(rt.data))
+	for i, d := range rt.data {
+		if len(rt.dependent) > 0 {
+			if err := resolveDependentFields(d, rt.dependent); err != nil {
+				return nil, err
+			}
+		}
+		ret[i] = d
+	}
+	return ret, nil
+}
+
+// TryAttributesCustom returns fields of trait with given overrides.
+// See TryAttributesCustomBatch.
+func (sdr *Seedr) TryAttributesCustom(traitName string, override Trait) (Trait, error) {
+	ret, err := sdr.TryAttributesCustomBatch(traitName, 1, override)
+	if err != nil {
+		return nil, err
+	}
+	return ret[0], nil
+}
+
+// TryAttributesBatch returns fields of n instances of trait.
+// See TryAttributesCustomBatch.
+func (sdr *Seedr) TryAttributesBatch(traitName string, n int) ([]Trait, error) {
+	return sdr.TryAttributesCustomBatch(traitName, n, nil)
+}
+
+// TryAttributes returns fields of trait.
+// See TryAttributesCustomBatch.
+func (sdr *Seedr) TryAttributes(traitName string) (Trait, error) {
+	return sdr.TryAttributesCustom(traitName, nil)
+}
+
+// AttributesCustomBatch is same as TryAttributesCustomBatch, but it panics on error.
+func (sdr *Seedr) AttributesCustomBatch(traitName string, n int, override Trait) []Trait {
+	ret, err := sdr.TryAttributesCustomBatch(traitName, n, override)
+	panicOnError(err)
+	return ret
+}
+
+// AttributesCustom is same as TryAttributesCustom, but it panics on error.
+func (sdr *Seedr) AttributesCustom(traitName string, override Trait) Trait {
+	ret, err := sdr.TryAttributesCustom(traitName, override)
+	panicOnError(err)
+	return ret
+}
+
+// AttributesBatch is same as TryAttributesBatch, but it panics on error.
+func (sdr *Seedr) AttributesBatch(traitName string, n int) []Trait {
+	ret, err := sdr.TryAttributesBatch(traitName, n)
+	panicOnError(err)
+	return ret
+}
+
+// Attributes is same as TryAttributes, but it panics on error.
+func (sdr *Seedr) Attributes(traitName string) Trait {
+	ret, err := sdr.TryAttributes(traitName)
+	panicOnError(err)
+	return ret
+}
+
+// jsonKeys maps Trait field names to JSON keys of fields of struct type t
+// using MapFieldFunc of Seedr in reverse. Embedded structs are flattened.
+func (sdr *Seedr) jsonKeys(t reflect.Type, keys map[string]string) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, tagged := f.Name, false
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+			if tn := strings.Split(tag, ",")[0]; tn != "" {
+				name, tagged = tn, true
+			}
+		}
+		if f.Anonymous && !tagged && f.Type.Kind() == reflect.Struct {
+			if err := sdr.jsonKeys(f.Type, keys); err != nil {
+				return err
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		fname, err := sdr.extractFieldName(f)
+		if err != nil {
+			return &ScanError{Field: f.Name, Err: err}
+		}
+		keys[fname] = name
+	}
+	return nil
+}
+
+// ToJSON encodes attrs (see Attributes) to JSON with keys
+// matching JSON keys of respective fields of model (struct or pointer to struct).
+// Trait fields are matched to struct fields using MapFieldFunc of Seedr (see SetFieldMapper).
+// Fields that don't match any struct field are encoded as is.
+func (sdr *Seedr) ToJSON(attrs Trait, model interface{}) ([]byte, error) {
+	t := reflect.TypeOf(model)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("ToJSON model must be struct or pointer to struct, %T was given", model)
+	}
+	keys := make(map[string]string)
+	if err := sdr.jsonKeys(t, keys); err != nil {
+		return nil, err
+	}
+	out := make(map[string]interface{}, len(attrs))
+	for k, v := range attrs {
+		if jk, ok := keys[k]; ok {
+			k = jk
+		}
+		out[k] = v
+	}
+	return json.Marshal(out)
+}
@@ -45,6 +45,24 @@ var errNilPtr = errors.New("destination pointer is nil") // embedded in descript
 // An error is returned if the copy would result in loss of information.
 // dest should be a pointer type.
 func convertAssign(dest, src interface{}) error {
+	// driver.Valuer (e.g. sql.NullString) is assigned as is if possible,
+	// otherwise it's converted to driver.Value first.
+	if vr, ok := src.(driver.Valuer); ok {
+		dpv, sv := reflect.ValueOf(dest), reflect.ValueOf(src)
+		if dpv.Kind() == reflect.Ptr && !dpv.IsNil() && sv.Type().AssignableTo(dpv.Type().Elem()) {
+			dpv.Elem().Set(sv)
+			return nil
+		}
+		if sv.Kind() == reflect.Ptr && sv.IsNil() {
+			src = nil
+		} else {
+			var err error
+			if src, err = vr.Value(); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Common cases, without reflect.
 	switch s := src.(type) {
 	case string:
@@ -1,5 +1,7 @@
 package driver
 
+import "context"
+
 // Payload for Driver.MustCreate
 type Payload struct {
 	// Entity is a name of database schema/table, index, etc.
@@ -15,6 +17,34 @@ type Payload struct {
 	ReturnFields []string
 	// Data is a list of objects to be created
 	Data []map[string]interface{}
+	// Conflict defines how records that already exist are handled (see Upserter).
+	// If nil, records are simply inserted.
+	Conflict *Conflict
+}
+
+// ConflictStrategy defines how Upserter handles record
+// that has same values of Conflict.Key fields as existing one.
+type ConflictStrategy int
+
+const (
+	// ConflictIgnore leaves existing record unchanged. Result contains given values,
+	// fields that were not given (e.g. auto-generated primary key) are taken from existing record.
+	ConflictIgnore ConflictStrategy = iota + 1
+	// ConflictUpdate updates Conflict.Update fields of existing record by given values.
+	// Result contains stored values.
+	ConflictUpdate
+	// ConflictFetch leaves existing record unchanged. Result contains stored values.
+	ConflictFetch
+)
+
+// Conflict configures upsert of Payload.
+type Conflict struct {
+	Strategy ConflictStrategy
+	// Key is a list of fields (usually unique index) that identify existing record.
+	// All of them must be listed in InsertFields. NULL values of Key fields match NULL.
+	Key []string
+	// Update is a list of fields updated by ConflictUpdate.
+	Update []string
 }
 
 // Driver holds implementation of particular storage behind
@@ -23,3 +53,70 @@ type Driver interface {
 	// if something goes wrong it should return meaningful error.
 	Create(Payload) (results []map[string]interface{}, err error)
 }
+
+// ContextDriver is a Driver that supports context cancellation.
+type ContextDriver interface {
+	Driver
+	// CreateContext is same as Create, but it must stop
+	// as soon as possible if ctx is canceled.
+	CreateContext(ctx context.Context, p Payload) (results []map[string]interface{}, err error)
+}
+
+// CreateContext calls CreateContext of ContextDriver.
+// For other drivers it checks if ctx is not done and falls back to Create.
+func CreateContext(ctx context.Context, d Driver, p Payload) ([]map[string]interface{}, error) {
+	if cd, ok := d.(ContextDriver); ok {
+		return cd.CreateContext(ctx, p)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return d.Create(p)
+}
+
+// Tx is a Driver bound to a database transaction.
+type Tx interface {
+	Driver
+	Commit() error
+	Rollback() error
+}
+
+// Transactor is implemented by drivers that support transactions.
+type Transactor interface {
+	// Begin starts a transaction.
+	// All records created by returned Tx are stored in this transaction.
+	Begin(ctx context.Context) (Tx, error)
+}
+
+// Deleter is implemented by drivers that can delete created records.
+type Deleter interface {
+	// Delete deletes records of Entity. Data contains records
+	// (as returned by Create) that are identified by PrimaryKey,
+	// or by values of all InsertFields if PrimaryKey is empty. In latter case
+	// every stored record with the same values is deleted, including identical
+	// records that were not created by Seedr.
+	Delete(ctx context.Context, p Payload) error
+}
+
+// Finder is implemented by drivers that can fetch stored records.
+type Finder interface {
+	// Find returns ReturnFields of records of Entity. Data contains
+	// records identified by PrimaryKey; results must be in the same order.
+	// It must return error if any of records does not exist.
+	Find(ctx context.Context, p Payload) (results []map[string]interface{}, err error)
+}
+
+// Upserter is implemented by drivers that support Payload.Conflict.
+type Upserter interface {
+	// Upsert is same as CreateContext, but records that already exist
+	// are handled according to p.Conflict. Results must be in the same order as Data,
+	// found[i] reports whether record of Data[i] existed before.
+	Upsert(ctx context.Context, p Payload) (results []map[string]interface{}, found []bool, err error)
+}
+
+// Updater is implemented by drivers that can update stored records.
+type Updater interface {
+	// Update stores new values of InsertFields of records of Entity.
+	// Data contains records identified by PrimaryKey.
+	Update(ctx context.Context, p Payload) error
+}
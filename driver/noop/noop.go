@@ -1,6 +1,10 @@
 package noop
 
-import "github.com/josephbuchma/seedr/driver"
+import (
+	"context"
+
+	"github.com/josephbuchma/seedr/driver"
+)
 
 // NoopDriver is a driver that does nothing
 type NoopDriver struct{}
@@ -9,3 +13,29 @@ type NoopDriver struct{}
 func (b NoopDriver) Create(p driver.Payload) ([]map[string]interface{}, error) {
 	return p.Data, nil
 }
+
+// CreateContext bypasses payload Data unless ctx is done.
+func (b NoopDriver) CreateContext(ctx context.Context, p driver.Payload) ([]map[string]interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return b.Create(p)
+}
+
+// Delete does nothing
+func (b NoopDriver) Delete(ctx context.Context, p driver.Payload) error {
+	return ctx.Err()
+}
+
+// Find bypasses payload Data unless ctx is done.
+func (b NoopDriver) Find(ctx context.Context, p driver.Payload) ([]map[string]interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return p.Data, nil
+}
+
+// Update does nothing
+func (b NoopDriver) Update(ctx context.Context, p driver.Payload) error {
+	return ctx.Err()
+}
@@ -1,23 +1,19 @@
 package sql
 
-import (
-	"bytes"
-	"fmt"
-	"strings"
-)
-
-type SetVarer interface {
-	SetVar(name string, val interface{}) string
-}
-
-type Placeholderer interface {
-	Placeholders(n int) string
-}
+import "bytes"
 
+// SQLBuilder builds SQL statements using given Dialect.
+// All identifiers are quoted and all values are passed as arguments of statement.
 type SQLBuilder struct {
 	bytes.Buffer
-	SetVarer
-	Placeholderer
+	dialect Dialect
+	// args is a number of placeholders written so far
+	args int
+}
+
+// NewBuilder returns new SQLBuilder for given Dialect.
+func NewBuilder(d Dialect) *SQLBuilder {
+	return &SQLBuilder{dialect: d}
 }
 
 func (s *SQLBuilder) sem() *SQLBuilder {
@@ -27,16 +23,39 @@ func (s *SQLBuilder) sem() *SQLBuilder {
 	return s
 }
 
+func (s *SQLBuilder) placeholder() {
+	s.args++
+	s.WriteString(s.dialect.Placeholder(s.args))
+}
+
 func (s *SQLBuilder) placeholders(n int) {
 	s.WriteString("(")
-	s.WriteString(s.Placeholderer.Placeholders(n))
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			s.WriteString(",")
+		}
+		s.placeholder()
+	}
 	s.WriteString(")")
 }
 
+func (s *SQLBuilder) idents(names []string) {
+	for i, n := range names {
+		if i > 0 {
+			s.WriteString(", ")
+		}
+		s.WriteString(s.dialect.QuoteIdent(n))
+	}
+}
+
+// Insert writes INSERT statement of n records.
 func (s *SQLBuilder) Insert(table string, fields []string, n int) *SQLBuilder {
-	iflds := strings.Join(fields, ", ")
 	s.sem()
-	s.WriteString(fmt.Sprintf("\nINSERT INTO %s (%s) VALUES ", table, iflds))
+	s.WriteString("\nINSERT INTO ")
+	s.WriteString(s.dialect.QuoteIdent(table))
+	s.WriteString(" (")
+	s.idents(fields)
+	s.WriteString(") VALUES ")
 	if n > 1 {
 		s.WriteString("\n")
 	}
@@ -48,45 +67,123 @@ func (s *SQLBuilder) Insert(table string, fields []string, n int) *SQLBuilder {
 	return s
 }
 
-func (s *SQLBuilder) SetVar(varName string, val interface{}) *SQLBuilder {
+// InsertDefault writes INSERT statement of single record
+// that has default values in all columns.
+func (s *SQLBuilder) InsertDefault(table string) *SQLBuilder {
+	s.sem()
+	s.WriteString("\nINSERT INTO ")
+	s.WriteString(s.dialect.QuoteIdent(table))
+	s.WriteString(" DEFAULT VALUES")
+	return s
+}
+
+// Returning writes RETURNING clause. It's omitted if there are no columns,
+// or if Dialect doesn't support it (see Dialect.Returning), so inserted
+// records must be fetched back according to Dialect.LastInsertID.
+func (s *SQLBuilder) Returning(cols []string) *SQLBuilder {
+	if len(cols) == 0 || !s.dialect.Returning() {
+		return s
+	}
+	s.WriteString(" RETURNING ")
+	s.idents(cols)
+	return s
+}
+
+// IgnoreConflict writes clause of Dialect that leaves existing record
+// unchanged (see Dialect.IgnoreConflict).
+func (s *SQLBuilder) IgnoreConflict(key []string) *SQLBuilder {
+	s.WriteString(s.dialect.IgnoreConflict(key))
+	return s
+}
+
+// Update writes UPDATE statement that sets given fields.
+func (s *SQLBuilder) Update(table string, fields []string) *SQLBuilder {
+	s.sem()
+	s.WriteString("\nUPDATE ")
+	s.WriteString(s.dialect.QuoteIdent(table))
+	s.WriteString(" SET ")
+	for i, f := range fields {
+		if i > 0 {
+			s.WriteString(", ")
+		}
+		s.WriteString(s.dialect.QuoteIdent(f))
+		s.WriteString("=")
+		s.placeholder()
+	}
+	return s
+}
+
+// Delete writes DELETE statement.
+func (s *SQLBuilder) Delete(table string) *SQLBuilder {
 	s.sem()
-	s.WriteString(s.SetVarer.SetVar(varName, val))
+	s.WriteString("\nDELETE FROM ")
+	s.WriteString(s.dialect.QuoteIdent(table))
 	return s
 }
 
+// Select writes SELECT statement of given columns.
 func (s *SQLBuilder) Select(cols []string) *SQLBuilder {
 	s.sem()
-	s.WriteString(fmt.Sprintf("\nSELECT %s ", strings.Join(cols, ", ")))
+	s.WriteString("\nSELECT ")
+	s.idents(cols)
+	s.WriteString(" ")
 	return s
 }
 
+// From writes FROM clause.
 func (s *SQLBuilder) From(table string) *SQLBuilder {
-	s.WriteString(fmt.Sprintf("FROM %s", table))
+	s.WriteString("FROM ")
+	s.WriteString(s.dialect.QuoteIdent(table))
 	return s
 }
 
+// Where writes WHERE clause with given column, it must be followed by condition (e.g. In).
 func (s *SQLBuilder) Where(col string) *SQLBuilder {
 	s.WriteString(" WHERE ")
-	s.WriteString(col)
+	s.WriteString(s.dialect.QuoteIdent(col))
 	return s
 }
 
-func (s *SQLBuilder) Eql(v interface{}) *SQLBuilder {
-	s.WriteString(fmt.Sprintf("=%v", v))
+// In writes IN condition with n values.
+func (s *SQLBuilder) In(n int) *SQLBuilder {
+	s.WriteString(" IN ")
+	s.placeholders(n)
 	return s
 }
 
-func (s *SQLBuilder) Between(a interface{}) *SQLBuilder {
-	s.WriteString(fmt.Sprintf(" BETWEEN %v", a))
+// WhereEq writes WHERE clause that matches values of all given columns.
+func (s *SQLBuilder) WhereEq(cols []string) *SQLBuilder {
+	s.WriteString(" WHERE ")
+	for i, c := range cols {
+		if i > 0 {
+			s.WriteString(" AND ")
+		}
+		s.WriteString(s.dialect.QuoteIdent(c))
+		s.WriteString("=")
+		s.placeholder()
+	}
 	return s
 }
 
-func (s *SQLBuilder) And(a interface{}) *SQLBuilder {
-	s.WriteString(fmt.Sprintf(" AND %v", a))
+// WhereMatch is same as WhereEq, but columns which are null[i]
+// are compared using IS NULL, so NULL values are matched too.
+func (s *SQLBuilder) WhereMatch(cols []string, null []bool) *SQLBuilder {
+	s.WriteString(" WHERE ")
+	for i, c := range cols {
+		if i > 0 {
+			s.WriteString(" AND ")
+		}
+		s.WriteString(s.dialect.QuoteIdent(c))
+		if null[i] {
+			s.WriteString(" IS NULL")
+			continue
+		}
+		s.WriteString("=")
+		s.placeholder()
+	}
 	return s
 }
 
 func (s *SQLBuilder) String() string {
-	str := s.Buffer.String()
-	return str
+	return s.Buffer.String()
 }
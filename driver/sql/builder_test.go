@@ -0,0 +1,64 @@
+package sql
+
+import "testing"
+
+func TestQuoteIdent(t *testing.T) {
+	testCases := []struct {
+		name, quote, expected string
+	}{
+		{"order", `"`, `"order"`},
+		{`we"ird`, `"`, `"we""ird"`},
+		{"public.users", `"`, `"public"."users"`},
+		{"key", "`", "`key`"},
+		{"we`ird", "`", "`we``ird`"},
+	}
+	for _, tc := range testCases {
+		if got := QuoteIdent(tc.name, tc.quote); got != tc.expected {
+			t.Errorf("QuoteIdent(%q, %q): expected %s, got %s", tc.name, tc.quote, tc.expected, got)
+		}
+	}
+}
+
+func TestSQLBuilder(t *testing.T) {
+	testCases := []struct {
+		got, expected string
+	}{
+		{
+			NewBuilder(qmarks{returning: true}).Insert("users", []string{"name", "email"}, 2).Returning([]string{"id"}).String(),
+			"\nINSERT INTO users (name, email) VALUES \n(?,?),\n(?,?) RETURNING id",
+		},
+		{
+			NewBuilder(qmarks{}).Insert("users", []string{"name"}, 1).Returning([]string{"id"}).String(),
+			"\nINSERT INTO users (name) VALUES (?)",
+		},
+		{
+			NewBuilder(qmarks{returning: true}).Insert("users", []string{"name"}, 1).Returning(nil).String(),
+			"\nINSERT INTO users (name) VALUES (?)",
+		},
+		{
+			NewBuilder(qmarks{returning: true}).InsertDefault("users").Returning([]string{"id"}).String(),
+			"\nINSERT INTO users DEFAULT VALUES RETURNING id",
+		},
+		{
+			NewBuilder(qmarks{}).Update("users", []string{"name", "email"}).WhereEq([]string{"id"}).String(),
+			"\nUPDATE users SET name=?, email=? WHERE id=?",
+		},
+		{
+			NewBuilder(qmarks{}).Select([]string{"id", "name"}).From("users").Where("id").In(3).String(),
+			"\nSELECT id, name FROM users WHERE id IN (?,?,?)",
+		},
+		{
+			NewBuilder(qmarks{}).Delete("users").WhereEq([]string{"a", "b"}).Delete("clubs").Where("id").In(1).String(),
+			"\nDELETE FROM users WHERE a=? AND b=?;\nDELETE FROM clubs WHERE id IN (?)",
+		},
+		{
+			OnConflict(qmarks{}, []string{"a", "b"}),
+			" ON CONFLICT (a, b) DO NOTHING",
+		},
+	}
+	for _, tc := range testCases {
+		if tc.got != tc.expected {
+			t.Errorf("Expected:\n%q\ngot:\n%q", tc.expected, tc.got)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package sql
+
+import (
+	"context"
+	sqldriver "database/sql/driver"
+	"errors"
+	"reflect"
+
+	"github.com/josephbuchma/seedr/driver"
+)
+
+// Delete deletes records of p (see driver.Deleter) using SQL of Dialect d.
+// Records are deleted by PrimaryKey in chunks of given size, or one by one
+// by values of all InsertFields if PrimaryKey is empty (NULL values are matched
+// by IS NULL, see SQLBuilder.WhereMatch). Without PrimaryKey every row that
+// matches a record is deleted, even if it was not created by Seedr (e.g. join
+// row of the same pair that existed before), so tables of factories that
+// may be cleaned up this way should have a primary key or unique constraint.
+func Delete(ctx context.Context, db DB, p driver.Payload, chunk int, d Dialect) error {
+	if p.PrimaryKey == "" {
+		if len(p.InsertFields) == 0 {
+			return errors.New("PrimaryKey or InsertFields are required to delete records")
+		}
+		for _, rec := range p.Data {
+			null, vals := matchValues(p.InsertFields, rec)
+			s := NewBuilder(d).Delete(p.Entity).WhereMatch(p.InsertFields, null).String()
+			if _, err := db.ExecContext(ctx, s, vals...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	pks := make([]interface{}, len(p.Data))
+	for i, rec := range p.Data {
+		pks[i] = rec[p.PrimaryKey]
+	}
+	for b := 0; b < len(pks); b += chunk {
+		e := b + chunk
+		if e > len(pks) {
+			e = len(pks)
+		}
+		s := NewBuilder(d).Delete(p.Entity).Where(p.PrimaryKey).In(e - b).String()
+		if _, err := db.ExecContext(ctx, s, pks[b:e]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchValues returns arguments of SQLBuilder.WhereMatch for given fields of rec:
+// which of fields are NULL, and values of the rest of them.
+func matchValues(fields []string, rec map[string]interface{}) (null []bool, vals []interface{}) {
+	null = make([]bool, len(fields))
+	for i, f := range fields {
+		if null[i] = isNull(rec[f]); !null[i] {
+			vals = append(vals, rec[f])
+		}
+	}
+	return null, vals
+}
+
+// isNull reports whether v is stored as NULL (e.g. nil or invalid sql.NullString).
+func isNull(v interface{}) bool {
+	if vr, ok := v.(sqldriver.Valuer); ok {
+		if rv := reflect.ValueOf(vr); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return true
+		}
+		val, err := vr.Value()
+		return err == nil && val == nil
+	}
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
@@ -0,0 +1,61 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	sqldriver "database/sql/driver"
+	"reflect"
+	"testing"
+
+	"github.com/josephbuchma/seedr/driver"
+	"github.com/josephbuchma/seedr/driver/sql/internal/fakedb"
+)
+
+// qmarks is a Dialect with "?" placeholders and without quoting of identifiers.
+type qmarks struct {
+	returning bool
+}
+
+func (qmarks) QuoteIdent(name string) string        { return name }
+func (qmarks) Placeholder(int) string               { return "?" }
+func (q qmarks) Returning() bool                    { return q.returning }
+func (q qmarks) IgnoreConflict(key []string) string { return OnConflict(q, key) }
+func (qmarks) LastInsertID() LastInsertID           { return LastInsertIDFirst }
+
+func TestDelete(t *testing.T) {
+	db, log := fakedb.Open(func(string, []sqldriver.Value) fakedb.Result {
+		return fakedb.Result{RowsAffected: 1}
+	})
+	defer db.Close()
+
+	p := driver.Payload{
+		Entity:       "users",
+		PrimaryKey:   "id",
+		InsertFields: []string{"name", "club_id"},
+		Data: []map[string]interface{}{
+			{"id": int64(1), "name": "a", "club_id": int64(7)},
+			{"id": int64(2), "name": "b", "club_id": int64(7)},
+			{"id": int64(3), "name": "c", "club_id": nil},
+			{"id": int64(4), "name": "d", "club_id": sql.NullInt64{}},
+		},
+	}
+	if err := Delete(context.Background(), db, p, 2, qmarks{}); err != nil {
+		t.Fatal(err)
+	}
+	p.PrimaryKey = ""
+	if err := Delete(context.Background(), db, p, 2, qmarks{}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []fakedb.Statement{
+		{Query: "\nDELETE FROM users WHERE id IN (?,?)", Args: []sqldriver.Value{int64(1), int64(2)}},
+		{Query: "\nDELETE FROM users WHERE id IN (?,?)", Args: []sqldriver.Value{int64(3), int64(4)}},
+		{Query: "\nDELETE FROM users WHERE name=? AND club_id=?", Args: []sqldriver.Value{"a", int64(7)}},
+		{Query: "\nDELETE FROM users WHERE name=? AND club_id=?", Args: []sqldriver.Value{"b", int64(7)}},
+		{Query: "\nDELETE FROM users WHERE name=? AND club_id IS NULL", Args: []sqldriver.Value{"c"}},
+		{Query: "\nDELETE FROM users WHERE name=? AND club_id IS NULL", Args: []sqldriver.Value{"d"}},
+	}
+	if got := log.Statements(); !reflect.DeepEqual(expected, got) {
+		t.Errorf("Expected:\n%q\ngot:\n%q", expected, got)
+	}
+}
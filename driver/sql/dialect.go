@@ -0,0 +1,71 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect describes syntax and capabilities of particular SQL database,
+// so SQLBuilder can be shared by all SQL drivers.
+type Dialect interface {
+	// QuoteIdent quotes identifier (table or column name), see QuoteIdent func.
+	QuoteIdent(name string) string
+	// Placeholder returns placeholder of i-th (starting from 1) argument of statement.
+	Placeholder(i int) string
+	// Returning reports whether INSERT ... RETURNING is supported,
+	// SQLBuilder.Returning writes nothing otherwise.
+	Returning() bool
+	// IgnoreConflict returns clause that is appended to INSERT statement,
+	// so existing record with same values of key columns is left unchanged
+	// and it's not counted by sql.Result.RowsAffected.
+	IgnoreConflict(key []string) string
+	// LastInsertID returns strategy of retrieval of auto-generated primary keys.
+	// It may depend on version of database, so drivers choose how records
+	// are inserted by it (e.g. SQLite without RETURNING fetches them by rowid).
+	LastInsertID() LastInsertID
+}
+
+// LastInsertID is a strategy of retrieval of auto-generated primary keys.
+type LastInsertID int
+
+const (
+	// LastInsertIDReturning means that keys are returned by INSERT ... RETURNING.
+	LastInsertIDReturning LastInsertID = iota
+	// LastInsertIDFirst means that sql.Result.LastInsertId returns key
+	// of the first record inserted by multi-row INSERT (e.g. MySQL).
+	LastInsertIDFirst
+	// LastInsertIDLast means that sql.Result.LastInsertId returns key
+	// of the last inserted record (e.g. SQLite).
+	LastInsertIDLast
+)
+
+// UnsupportedLastInsertID returns error of driver that can't insert records
+// using LastInsertID strategy of d.
+func UnsupportedLastInsertID(d Dialect) error {
+	return fmt.Errorf("LastInsertID strategy %d of %T is not supported", d.LastInsertID(), d)
+}
+
+// QuoteIdent quotes name using given quote character, quotes inside of name are doubled.
+// Qualified names (e.g. "schema.table") are quoted part by part.
+func QuoteIdent(name, quote string) string {
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = quote + strings.ReplaceAll(p, quote, quote+quote) + quote
+	}
+	return strings.Join(parts, ".")
+}
+
+// OnConflict returns ON CONFLICT ... DO NOTHING clause of Dialect.IgnoreConflict
+// for databases that support it (PostgreSQL and SQLite).
+func OnConflict(d Dialect, key []string) string {
+	var s strings.Builder
+	s.WriteString(" ON CONFLICT (")
+	for i, k := range key {
+		if i > 0 {
+			s.WriteString(", ")
+		}
+		s.WriteString(d.QuoteIdent(k))
+	}
+	s.WriteString(") DO NOTHING")
+	return s.String()
+}
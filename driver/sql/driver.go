@@ -0,0 +1,104 @@
+package sql
+
+import (
+	"context"
+
+	"github.com/josephbuchma/seedr/driver"
+)
+
+// InsertFunc inserts Data of p into Entity table using db and returns
+// inserted records (ReturnFields only) in the same order as given in payload.
+// It's the only part of Seedr driver that is specific to each database.
+type InsertFunc func(ctx context.Context, db DB, p driver.Payload) ([]map[string]interface{}, error)
+
+// Driver is a Seedr driver that creates records using InsertFunc,
+// and implements optional capabilities (driver.Deleter, driver.Finder,
+// driver.Upserter, driver.Updater and driver.Transactor) using SQL of Dialect.
+// It's embedded by SQL drivers (see mysql, postgres and sqlite packages).
+type Driver struct {
+	db      DB
+	dialect Dialect
+	// chunk is a max number of primary keys in single statement of Find and Delete
+	chunk  int
+	insert InsertFunc
+}
+
+// NewDriver creates Driver that uses given db and Dialect.
+// db is usually *sql.DB, but it also may be *sql.Tx (in this case records
+// are created within given transaction, and nested transactions started
+// by Begin are emulated using SAVEPOINT).
+// chunk is a max number of primary keys in single statement of Find and Delete.
+func NewDriver(db DB, d Dialect, chunk int, insert InsertFunc) *Driver {
+	return &Driver{db: db, dialect: d, chunk: chunk, insert: insert}
+}
+
+// Create is same as CreateContext with context.Background().
+func (d *Driver) Create(p driver.Payload) (results []map[string]interface{}, err error) {
+	return d.CreateContext(context.Background(), p)
+}
+
+// CreateContext inserts payload Data into database and returns inserted records
+// (see InsertFunc). Entity is a table name. All records are inserted
+// within single transaction (or savepoint).
+func (d *Driver) CreateContext(ctx context.Context, p driver.Payload) (results []map[string]interface{}, err error) {
+	if !CanBegin(d.db) {
+		return d.insert(ctx, d.db, p)
+	}
+	tx, err := Begin(ctx, d.db)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := d.insert(ctx, tx, p)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	err = tx.Commit()
+	return ret, err
+}
+
+// Begin starts a transaction (or savepoint, if driver is already bound to transaction).
+// Records created by returned driver are visible to others only after Commit.
+func (d *Driver) Begin(ctx context.Context) (driver.Tx, error) {
+	tx, err := Begin(ctx, d.db)
+	if err != nil {
+		return nil, err
+	}
+	return &driverTx{NewDriver(tx, d.dialect, d.chunk, d.insert), tx}, nil
+}
+
+type driverTx struct {
+	*Driver
+	tx *Tx
+}
+
+// Commit commits transaction
+func (t *driverTx) Commit() error {
+	return t.tx.Commit()
+}
+
+// Rollback aborts transaction
+func (t *driverTx) Rollback() error {
+	return t.tx.Rollback()
+}
+
+// Delete deletes records of Entity (see Delete func).
+func (d *Driver) Delete(ctx context.Context, p driver.Payload) error {
+	return Delete(ctx, d.db, p, d.chunk, d.dialect)
+}
+
+// Find fetches records of Entity by values of PrimaryKey (see Find func).
+func (d *Driver) Find(ctx context.Context, p driver.Payload) ([]map[string]interface{}, error) {
+	return Find(ctx, d.db, p, d.chunk, d.dialect)
+}
+
+// Upsert inserts records of Entity, existing records (by values of Conflict.Key)
+// are handled according to Conflict.Strategy (see Upsert func).
+func (d *Driver) Upsert(ctx context.Context, p driver.Payload) ([]map[string]interface{}, []bool, error) {
+	return Upsert(ctx, d.db, p, d.dialect)
+}
+
+// Update updates InsertFields of records of Entity by values of PrimaryKey (see Update func).
+func (d *Driver) Update(ctx context.Context, p driver.Payload) error {
+	return Update(ctx, d.db, p, d.dialect)
+}
@@ -0,0 +1,91 @@
+package sql
+
+import (
+	"context"
+	sqldriver "database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/josephbuchma/seedr/driver"
+)
+
+// Find fetches records of p (see driver.Finder) by PrimaryKey
+// in chunks of given size using SQL of Dialect d.
+func Find(ctx context.Context, db DB, p driver.Payload, chunk int, d Dialect) ([]map[string]interface{}, error) {
+	if p.PrimaryKey == "" {
+		return nil, errors.New("PrimaryKey is required to find records")
+	}
+	fields := p.ReturnFields
+	pkIdx := -1
+	for i, f := range fields {
+		if f == p.PrimaryKey {
+			pkIdx = i
+		}
+	}
+	if pkIdx == -1 {
+		pkIdx = len(fields)
+		fields = append(fields[:len(fields):len(fields)], p.PrimaryKey)
+	}
+
+	found := make(map[string]map[string]interface{}, len(p.Data))
+	pks := make([]interface{}, len(p.Data))
+	for i, rec := range p.Data {
+		pks[i] = rec[p.PrimaryKey]
+	}
+	for b := 0; b < len(pks); b += chunk {
+		e := b + chunk
+		if e > len(pks) {
+			e = len(pks)
+		}
+		s := NewBuilder(d).Select(fields).From(p.Entity).Where(p.PrimaryKey).In(e - b).String()
+		rows, err := db.QueryContext(ctx, s, pks[b:e]...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			vals := make([]interface{}, len(fields))
+			ptrs := make([]interface{}, len(fields))
+			for i := range vals {
+				ptrs[i] = &vals[i]
+			}
+			if err := rows.Scan(ptrs...); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			rec := make(map[string]interface{}, len(p.ReturnFields))
+			for i, f := range p.ReturnFields {
+				rec[f] = vals[i]
+			}
+			found[pkKey(vals[pkIdx])] = rec
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	ret := make([]map[string]interface{}, len(p.Data))
+	for i, pk := range pks {
+		rec, ok := found[pkKey(pk)]
+		if !ok {
+			return nil, fmt.Errorf("record of %s with %s=%v not found", p.Entity, p.PrimaryKey, pk)
+		}
+		ret[i] = rec
+	}
+	return ret, nil
+}
+
+// pkKey makes primary key values comparable regardless of
+// how they were returned by database driver. Given values that
+// implement driver.Valuer (e.g. binary UUIDs) are compared by their Value.
+func pkKey(v interface{}) string {
+	if vr, ok := v.(sqldriver.Valuer); ok {
+		if val, err := vr.Value(); err == nil {
+			v = val
+		}
+	}
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
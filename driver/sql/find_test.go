@@ -0,0 +1,80 @@
+package sql
+
+import (
+	"context"
+	sqldriver "database/sql/driver"
+	"reflect"
+	"testing"
+
+	"github.com/josephbuchma/seedr/driver"
+	"github.com/josephbuchma/seedr/driver/sql/internal/fakedb"
+)
+
+// binaryKey is a primary key that is stored as bytes (e.g. binary UUID).
+type binaryKey string
+
+func (k binaryKey) Value() (sqldriver.Value, error) {
+	return []byte(k), nil
+}
+
+func TestFindValuerKeys(t *testing.T) {
+	db, _ := fakedb.Open(func(string, []sqldriver.Value) fakedb.Result {
+		return fakedb.Result{
+			Columns: []string{"id"},
+			Rows:    [][]sqldriver.Value{{[]byte("k2")}, {[]byte("k1")}},
+		}
+	})
+	defer db.Close()
+
+	p := driver.Payload{
+		Entity:       "users",
+		PrimaryKey:   "id",
+		ReturnFields: []string{"id"},
+		Data:         []map[string]interface{}{{"id": binaryKey("k1")}, {"id": binaryKey("k2")}},
+	}
+	res, err := Find(context.Background(), db, p, 10, qmarks{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []map[string]interface{}{{"id": []byte("k1")}, {"id": []byte("k2")}}
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("Expected:\n%#v\ngot:\n%#v", expected, res)
+	}
+}
+
+func TestFind(t *testing.T) {
+	db, log := fakedb.Open(func(string, []sqldriver.Value) fakedb.Result {
+		// rows are returned in different order, with primary key as text
+		return fakedb.Result{
+			Columns: []string{"name", "id"},
+			Rows: [][]sqldriver.Value{
+				{"b", []byte("2")},
+				{"a", []byte("1")},
+			},
+		}
+	})
+	defer db.Close()
+
+	p := driver.Payload{
+		Entity:       "users",
+		PrimaryKey:   "id",
+		ReturnFields: []string{"name"},
+		Data:         []map[string]interface{}{{"id": int64(1)}, {"id": int64(2)}},
+	}
+	res, err := Find(context.Background(), db, p, 10, qmarks{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []map[string]interface{}{{"name": "a"}, {"name": "b"}}
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("Expected:\n%#v\ngot:\n%#v", expected, res)
+	}
+	if q := log.Queries()[0]; q != "\nSELECT name, id FROM users WHERE id IN (?,?)" {
+		t.Errorf("Unexpected query %q", q)
+	}
+
+	p.Data = append(p.Data, map[string]interface{}{"id": int64(3)})
+	if _, err := Find(context.Background(), db, p, 10, qmarks{}); err == nil {
+		t.Error("Expected error for missing record")
+	}
+}
@@ -0,0 +1,216 @@
+// Package fakedb is a scriptable database/sql driver.
+// It is used by unit tests of Seedr SQL drivers to verify
+// generated statements without live database server.
+package fakedb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+)
+
+// Result is a response of Handler to executed statement.
+type Result struct {
+	Columns      []string
+	Rows         [][]driver.Value
+	LastInsertID int64
+	RowsAffected int64
+	Err          error
+}
+
+// Handler responds to every Exec and Query.
+type Handler func(query string, args []driver.Value) Result
+
+// Statement is a record of executed statement
+// or transaction event ("BEGIN", "COMMIT", "ROLLBACK").
+type Statement struct {
+	Query string
+	Args  []driver.Value
+}
+
+// Log is a list of executed statements.
+type Log struct {
+	mu    sync.Mutex
+	stmts []Statement
+}
+
+func (l *Log) add(query string, args []driver.Value) {
+	l.mu.Lock()
+	l.stmts = append(l.stmts, Statement{query, args})
+	l.mu.Unlock()
+}
+
+// Statements returns copy of all statements executed so far.
+func (l *Log) Statements() []Statement {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Statement(nil), l.stmts...)
+}
+
+// Queries returns only query strings of executed statements.
+func (l *Log) Queries() []string {
+	stmts := l.Statements()
+	ret := make([]string, len(stmts))
+	for i, s := range stmts {
+		ret[i] = s.Query
+	}
+	return ret
+}
+
+// Open returns *sql.DB that is backed by given handler.
+// If h is nil, every statement succeeds with empty result.
+func Open(h Handler) (*sql.DB, *Log) {
+	if h == nil {
+		h = func(string, []driver.Value) Result { return Result{} }
+	}
+	c := &connector{h: h, log: &Log{}}
+	return sql.OpenDB(c), c.log
+}
+
+type connector struct {
+	h   Handler
+	log *Log
+}
+
+func (c *connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{c}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return drv{c}
+}
+
+type drv struct {
+	c *connector
+}
+
+func (d drv) Open(string) (driver.Conn, error) {
+	return &conn{d.c}, nil
+}
+
+type conn struct {
+	c *connector
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{c, query}, nil
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	c.c.log.add("BEGIN", nil)
+	return tx{c}, nil
+}
+
+func (c *conn) exec(query string, args []driver.Value) (driver.Result, error) {
+	c.c.log.add(query, args)
+	r := c.c.h(query, args)
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	return result{r}, nil
+}
+
+func (c *conn) query(query string, args []driver.Value) (driver.Rows, error) {
+	c.c.log.add(query, args)
+	r := c.c.h(query, args)
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	return &rows{r: r}, nil
+}
+
+func (c *conn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.exec(query, values(args))
+}
+
+func (c *conn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.query(query, values(args))
+}
+
+func values(args []driver.NamedValue) []driver.Value {
+	vals := make([]driver.Value, len(args))
+	for i, a := range args {
+		vals[i] = a.Value
+	}
+	return vals
+}
+
+type tx struct {
+	c *conn
+}
+
+func (t tx) Commit() error {
+	t.c.c.log.add("COMMIT", nil)
+	return nil
+}
+
+func (t tx) Rollback() error {
+	t.c.c.log.add("ROLLBACK", nil)
+	return nil
+}
+
+type stmt struct {
+	c     *conn
+	query string
+}
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.c.exec(s.query, args)
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.c.query(s.query, args)
+}
+
+type result struct {
+	r Result
+}
+
+func (r result) LastInsertId() (int64, error) {
+	return r.r.LastInsertID, nil
+}
+
+func (r result) RowsAffected() (int64, error) {
+	return r.r.RowsAffected, nil
+}
+
+type rows struct {
+	r Result
+	i int
+}
+
+func (r *rows) Columns() []string {
+	return r.r.Columns
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.i >= len(r.r.Rows) {
+		return io.EOF
+	}
+	row := r.r.Rows[r.i]
+	if len(row) != len(dest) {
+		return errors.New("fakedb: invalid row length")
+	}
+	copy(dest, row)
+	r.i++
+	return nil
+}
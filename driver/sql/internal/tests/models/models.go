@@ -4,9 +4,10 @@
 package models
 
 import (
+	"database/sql"
 	"time"
 
-	"github.com/go-sql-driver/mysql"
+	"github.com/josephbuchma/seedr"
 )
 
 type User struct {
@@ -14,7 +15,7 @@ type User struct {
 	Email     string
 	Name      string
 	Active    bool
-	Checkin   mysql.NullTime
+	Checkin   sql.NullTime
 	CreatedAt time.Time
 }
 
@@ -31,6 +32,31 @@ type Club struct {
 	Name string
 }
 
+// Tag has client-generated ULID primary key.
+type Tag struct {
+	ID   string
+	Name string
+}
+
+// ArticleTag has client-generated binary UUID primary key.
+type ArticleTag struct {
+	ID        seedr.BinaryUUID
+	ArticleID int
+	TagID     string
+}
+
+// Label has client-generated primary key named uuid.
+type Label struct {
+	UUID string
+	Name string
+}
+
+// Visit has only fields with default values.
+type Visit struct {
+	ID        int
+	CreatedAt time.Time
+}
+
 type HellotaFields struct {
 	A int
 	B string
@@ -0,0 +1,42 @@
+package seedrs
+
+import (
+	. "github.com/josephbuchma/seedr"
+	"github.com/josephbuchma/seedr/driver/sql/internal/tests/util"
+)
+
+func articles() Factory {
+	return Factory{
+		FactoryConfig{
+			Entity:     "articles",
+			PrimaryKey: "id",
+		},
+		Relations{
+			"author": BelongsTo("users", "author_id"),
+		},
+		Traits{
+			"basic": {
+				"id":         Auto(),
+				"author_id":  nil,
+				"title":      SequenceString("Awesome Title %d"),
+				"body":       "test body value",
+				"created_at": util.TimeNowSecondPrecision(),
+			},
+			"withTestAuthor": {
+				"author": CreateRelated("TestUser"),
+			},
+
+			"withTestTime": {
+				"created_at": util.TestTime,
+			},
+
+			"Article": {
+				Include: "basic",
+			},
+
+			"TestArticle": {
+				Include: "basic withTestAuthor withTestTime",
+			},
+		},
+	}
+}
@@ -0,0 +1,53 @@
+package seedrs
+
+import . "github.com/josephbuchma/seedr"
+
+func clubs() Factory {
+	return Factory{
+		FactoryConfig{
+			Entity:     "clubs",
+			PrimaryKey: "id",
+		},
+		Relations{
+			"users": HasManyThrough("ClubToUser", "club_id", "user_id"),
+		},
+		Traits{
+			"basic": {
+				"id":   Auto(),
+				"name": SequenceString("Club-%d"),
+			},
+
+			"withUsers": {
+				Include: "basic",
+			},
+
+			"Club": {
+				Include: "basic",
+			},
+
+			"ClubWithUsers": {
+				Include: "basic",
+				"users": CreateRelatedBatch("TestUser", 2),
+			},
+		},
+	}
+}
+
+func clubsToUsers() Factory {
+	return Factory{
+		FactoryConfig{
+			Entity:     "clubs_to_users",
+			PrimaryKey: "id",
+		},
+		Relations{
+			"club_id": BelongsTo("clubs"),
+			"user_id": BelongsTo("users"),
+		},
+		Traits{
+			"ClubToUser": {
+				"club_id": CreateRelated("Club"),
+				"user_id": CreateRelated("User"),
+			},
+		},
+	}
+}
@@ -0,0 +1,29 @@
+package seedrs
+
+import (
+	"github.com/josephbuchma/seedr"
+	"github.com/josephbuchma/seedr/driver"
+)
+
+// NewTestSeedr creates test Seedr with all test factories.
+// Given driver is used as "create" driver.
+func NewTestSeedr(drv driver.Driver, config ...seedr.ConfigFunc) *seedr.Seedr {
+	return seedr.New("test_seedr", append([]seedr.ConfigFunc{
+		seedr.SetCreateDriver(drv),
+		seedr.SetFieldMapper(
+			seedr.RegexpTagFieldMapper(
+				`.*gorm:"column:\s*(\w+).*"`, seedr.SnakeFieldMapper(),
+			),
+		),
+	}, config...)...).
+		Add("users", users()).
+		Add("articles", articles()).
+		Add("clubs", clubs()).
+		Add("clubs_to_users", clubsToUsers()).
+		Add("tags", tags()).
+		Add("articles_to_tags", articlesToTags()).
+		Add("labels", labels()).
+		Add("tags_to_labels", tagsToLabels()).
+		Add("hellota_fields", hellotaFields()).
+		Add("visits", visits())
+}
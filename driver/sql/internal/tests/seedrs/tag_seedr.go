@@ -0,0 +1,106 @@
+package seedrs
+
+import . "github.com/josephbuchma/seedr"
+
+// tags have client-generated primary keys (see RunClientKeys)
+func tags() Factory {
+	return Factory{
+		FactoryConfig{
+			Entity:     "tags",
+			PrimaryKey: "id",
+		},
+		Relations{
+			"articles": HasManyThrough("ArticleTag", "tag_id", "article_id"),
+			"links":    HasMany("articles_to_tags", "tag_id"),
+			"labels":   HasManyThrough("TagLabel", "tag_id", "label_uuid"),
+		},
+		Traits{
+			"Tag": {
+				"id":   ULID(),
+				"name": SequenceString("Tag-%d"),
+			},
+
+			"TagWithArticles": {
+				Include:    "Tag",
+				"articles": CreateRelatedBatch("TestArticle", 2),
+			},
+
+			"TagWithLinks": {
+				Include: "Tag",
+				"links": CreateRelatedBatch("ArticleTagWithArticle", 2),
+			},
+
+			"TagWithLabels": {
+				Include:  "Tag",
+				"labels": CreateRelatedBatch("Label", 2),
+			},
+		},
+	}
+}
+
+// labels have primary key named other than id
+func labels() Factory {
+	return Factory{
+		FactoryConfig{
+			Entity:     "labels",
+			PrimaryKey: "uuid",
+		},
+		Relations{},
+		Traits{
+			"Label": {
+				"uuid": UUIDString(),
+				"name": SequenceString("Label-%d"),
+			},
+		},
+	}
+}
+
+func tagsToLabels() Factory {
+	return Factory{
+		FactoryConfig{
+			Entity:     "tags_to_labels",
+			PrimaryKey: "id",
+		},
+		Relations{
+			"tag_id":     BelongsTo("tags"),
+			"label_uuid": BelongsTo("labels"),
+		},
+		Traits{
+			"TagLabel": {
+				"id":         Auto(),
+				"tag_id":     nil,
+				"label_uuid": nil,
+			},
+		},
+	}
+}
+
+func articlesToTags() Factory {
+	return Factory{
+		FactoryConfig{
+			Entity:     "articles_to_tags",
+			PrimaryKey: "id",
+		},
+		Relations{
+			"article": BelongsTo("articles", "article_id"),
+			"tag":     BelongsTo("tags", "tag_id"),
+		},
+		Traits{
+			"ArticleTag": {
+				"id":         UUID(),
+				"article_id": nil,
+				"tag_id":     nil,
+			},
+
+			"ArticleTagWithArticle": {
+				Include:   "ArticleTag",
+				"article": CreateRelated("TestArticle"),
+			},
+
+			"ArticleTagWithTag": {
+				Include: "ArticleTagWithArticle",
+				"tag":   CreateRelated("Tag"),
+			},
+		},
+	}
+}
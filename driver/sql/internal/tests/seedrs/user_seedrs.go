@@ -0,0 +1,56 @@
+package seedrs
+
+import (
+	. "github.com/josephbuchma/seedr"
+	"github.com/josephbuchma/seedr/driver/sql/internal/tests/util"
+)
+
+func users() Factory {
+	return Factory{
+		FactoryConfig{
+			Entity:     "users",
+			PrimaryKey: "id",
+			UniqueBy:   []string{"email"},
+		},
+		Relations{
+			"articles": HasMany("articles", "author_id"),
+		},
+		Traits{
+			"basic": {
+				"id":         Auto(),
+				"name":       SequenceString("Agent Smith %d"),
+				"email":      SequenceString("agentsmith-%d@gmail.com"),
+				"active":     true,
+				"checkin":    util.TimeNowSecondPrecision(),
+				"created_at": util.TimeNowSecondPrecision(),
+			},
+			"inactive": {
+				"active": false,
+			},
+			"withTestTime": {
+				"created_at": util.TestTime,
+				"checkin":    util.TestTime,
+			},
+
+			// Public:
+
+			"User": {
+				Include: "basic",
+			},
+			"TestUser": {
+				Include: "basic withTestTime",
+			},
+			"InactiveUser": {
+				Include: "TestUser inactive",
+			},
+			"UserJohn": {
+				Include: "TestUser",
+				"name":  "John",
+			},
+			"UserHeavyWriter": {
+				Include:    "TestUser withTestTime",
+				"articles": CreateRelatedBatch("Article", 2),
+			},
+		},
+	}
+}
@@ -0,0 +1,20 @@
+package seedrs
+
+import . "github.com/josephbuchma/seedr"
+
+// visits have only fields with default values
+func visits() Factory {
+	return Factory{
+		FactoryConfig{
+			Entity:     "visits",
+			PrimaryKey: "id",
+		},
+		Relations{},
+		Traits{
+			"Visit": {
+				"id":         Auto(),
+				"created_at": Auto(),
+			},
+		},
+	}
+}
@@ -0,0 +1,768 @@
+// Package tests contains test cases shared by SQL drivers.
+// Every driver runs them against its own database
+// using Seedr created by seedrs.NewTestSeedr.
+package tests
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/josephbuchma/seedr"
+	"github.com/josephbuchma/seedr/driver"
+	"github.com/josephbuchma/seedr/driver/sql/internal/tests/models"
+	"github.com/josephbuchma/seedr/driver/sql/internal/tests/seedrs"
+	"github.com/josephbuchma/seedr/driver/sql/internal/tests/util"
+	"github.com/josephbuchma/seedr/seedrtest"
+)
+
+// Rebind converts "?" placeholders of raw queries used by test cases.
+// Drivers of databases with other placeholders (e.g. PostgreSQL) replace it,
+// see DollarPlaceholders.
+var Rebind = func(query string) string { return query }
+
+// DollarPlaceholders replaces "?" placeholders of query with $1, $2, ...
+func DollarPlaceholders(query string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+		n++
+		b.WriteString("$" + strconv.Itoa(n))
+	}
+	return b.String()
+}
+
+// Run runs shared test cases against given Seedr (see seedrs.NewTestSeedr).
+// Database must be empty.
+func Run(t *testing.T, sdr *seedr.Seedr) {
+	t.Run("Basic insert", func(t *testing.T) {
+		expextedUser := models.User{
+			ID:        1,
+			Name:      "Agent Smith 1",
+			Email:     "agentsmith-1@gmail.com",
+			Active:    false,
+			Checkin:   sql.NullTime{Time: util.TestTime, Valid: true},
+			CreatedAt: util.TestTime,
+		}
+		u := models.User{}
+		sdr.Create("InactiveUser").Scan(&u)
+		util.AssertDeepEqual(t, expextedUser, u)
+	})
+
+	t.Run("Insert with ForeignKey", func(t *testing.T) {
+		expectedArticle := models.Article{
+			ID:        1,
+			UserID:    2,
+			Title:     "Awesome Title 1",
+			Body:      "test body value",
+			CreatedAt: util.TestTime,
+		}
+
+		expectedAuthor := models.User{
+			ID:        2,
+			Name:      "Agent Smith 2",
+			Email:     "agentsmith-2@gmail.com",
+			Active:    true,
+			Checkin:   sql.NullTime{Time: util.TestTime, Valid: true},
+			CreatedAt: util.TestTime,
+		}
+
+		a := models.Article{}
+		u := models.User{}
+		ins := sdr.Create("TestArticle")
+		ins.Scan(&a)
+		ins.ScanRelated("author", &u)
+
+		util.AssertDeepEqual(t, expectedArticle, a)
+		util.AssertDeepEqual(t, expectedAuthor, u)
+	})
+
+	t.Run("Batch insert with ForeignKey", func(t *testing.T) {
+		expectedArticles := []models.Article{
+			{
+				ID:        2,
+				UserID:    3,
+				Title:     "Awesome Title 2",
+				Body:      "test body value",
+				CreatedAt: util.TestTime,
+			},
+			{
+				ID:        3,
+				UserID:    4,
+				Title:     "Awesome Title 3",
+				Body:      "test body value",
+				CreatedAt: util.TestTime,
+			},
+		}
+
+		expectedAuthors := []models.User{
+			{
+				ID:        3,
+				Name:      "Agent Smith 3",
+				Email:     "agentsmith-3@gmail.com",
+				Active:    true,
+				Checkin:   sql.NullTime{Time: util.TestTime, Valid: true},
+				CreatedAt: util.TestTime,
+			},
+			{
+				ID:        4,
+				Name:      "Agent Smith 4",
+				Email:     "agentsmith-4@gmail.com",
+				Active:    true,
+				Checkin:   sql.NullTime{Time: util.TestTime, Valid: true},
+				CreatedAt: util.TestTime,
+			},
+		}
+
+		var articles []models.Article
+		insArticles := sdr.CreateBatch("TestArticle", 2)
+		insArticles.Scan(&articles)
+
+		util.AssertDeepEqual(t, expectedArticles, articles)
+
+		for i := 0; i < 2; i++ {
+			u := models.User{}
+			a := models.Article{}
+			insArticles.Index(i).Scan(&a).ScanRelated("author", &u)
+
+			util.AssertDeepEqual(t, expectedArticles[i], a)
+			util.AssertDeepEqual(t, expectedAuthors[i], u)
+		}
+	})
+
+	t.Run("Insert with batch child related records", func(t *testing.T) {
+		usr := models.User{}
+		articles := []models.Article(nil)
+		sdr.Create("UserHeavyWriter").Scan(&usr).Related("articles").Scan(&articles)
+
+		expextedUser := models.User{
+			ID:        5,
+			Name:      "Agent Smith 5",
+			Email:     "agentsmith-5@gmail.com",
+			Active:    true,
+			Checkin:   sql.NullTime{Time: util.TestTime, Valid: true},
+			CreatedAt: util.TestTime,
+		}
+		expectedArticles := []models.Article{
+			{
+				ID:        4,
+				UserID:    5,
+				Title:     "Awesome Title 4",
+				Body:      "test body value",
+				CreatedAt: util.TestTime,
+			},
+			{
+				ID:        5,
+				UserID:    5,
+				Title:     "Awesome Title 5",
+				Body:      "test body value",
+				CreatedAt: util.TestTime,
+			},
+		}
+
+		util.AssertDeepEqual(t, expextedUser, usr)
+		util.AssertDeepEqual(t, expectedArticles, articles)
+	})
+
+	t.Run("Batch insert with batch child related records", func(t *testing.T) {
+		var usrs []models.User
+		articles := []models.Article(nil)
+		sdr.CreateBatch("UserHeavyWriter", 2).Scan(&usrs).Index(0).Related("articles").Scan(&articles)
+
+		expextedUsers := []models.User{
+			{
+				ID:        6,
+				Name:      "Agent Smith 6",
+				Email:     "agentsmith-6@gmail.com",
+				Active:    true,
+				Checkin:   sql.NullTime{Time: util.TestTime, Valid: true},
+				CreatedAt: util.TestTime,
+			},
+			{
+				ID:        7,
+				Name:      "Agent Smith 7",
+				Email:     "agentsmith-7@gmail.com",
+				Active:    true,
+				Checkin:   sql.NullTime{Time: util.TestTime, Valid: true},
+				CreatedAt: util.TestTime,
+			},
+		}
+		util.AssertDeepEqual(t, expextedUsers, usrs)
+	})
+
+	t.Run("Many to many insert", func(t *testing.T) {
+		usrs := []models.User(nil)
+		var club models.Club
+		cwu := sdr.Create("ClubWithUsers")
+		cwu.Scan(&club)
+		chusrs := cwu.Related("users")
+		chusrs.Scan(&usrs)
+
+		expectedClub := models.Club{
+			ID:   1,
+			Name: "Club-1",
+		}
+
+		util.AssertDeepEqual(t, expectedClub, club)
+
+		expextedUsers := []models.User{
+			{
+				ID:        8,
+				Name:      "Agent Smith 8",
+				Email:     "agentsmith-8@gmail.com",
+				Active:    true,
+				Checkin:   sql.NullTime{Time: util.TestTime, Valid: true},
+				CreatedAt: util.TestTime,
+			},
+			{
+				ID:        9,
+				Name:      "Agent Smith 9",
+				Email:     "agentsmith-9@gmail.com",
+				Active:    true,
+				Checkin:   sql.NullTime{Time: util.TestTime, Valid: true},
+				CreatedAt: util.TestTime,
+			},
+		}
+		util.AssertDeepEqual(t, expextedUsers, usrs)
+	})
+
+	t.Run("CreateRelatedBatch M2M", func(t *testing.T) {
+		club := models.Club{}
+		usrs := []models.User{}
+		sdr.Create("Club").CreateRelatedBatch("users", "TestUser", 2).Scan(&club).ScanRelated("users", &usrs)
+
+		expextedUsers := []models.User{
+			{
+				ID:        10,
+				Name:      "Agent Smith 10",
+				Email:     "agentsmith-10@gmail.com",
+				Active:    true,
+				Checkin:   sql.NullTime{Time: util.TestTime, Valid: true},
+				CreatedAt: util.TestTime,
+			},
+			{
+				ID:        11,
+				Name:      "Agent Smith 11",
+				Email:     "agentsmith-11@gmail.com",
+				Active:    true,
+				Checkin:   sql.NullTime{Time: util.TestTime, Valid: true},
+				CreatedAt: util.TestTime,
+			},
+		}
+		util.AssertDeepEqual(t, models.Club{ID: 2, Name: "Club-2"}, club)
+		util.AssertDeepEqual(t, expextedUsers, usrs)
+	})
+
+	t.Run("CreateRelatedBatch direct childs", func(t *testing.T) {
+		usr := models.User{}
+		articles := []models.Article{}
+		sdr.Create("User").CreateRelatedBatch("articles", "Article", 2).Scan(&usr).ScanRelated("articles", &articles)
+
+		expectedArticles := []models.Article{
+			{
+				ID:        10,
+				UserID:    12,
+				Title:     "Awesome Title 10",
+				Body:      "test body value",
+				CreatedAt: util.TestTime,
+			},
+			{
+				ID:        11,
+				UserID:    12,
+				Title:     "Awesome Title 11",
+				Body:      "test body value",
+				CreatedAt: util.TestTime,
+			},
+		}
+
+		util.AssertDeepEqual(t, models.User{
+			ID:        12,
+			Name:      "Agent Smith 12",
+			Email:     "agentsmith-12@gmail.com",
+			Active:    true,
+			Checkin:   sql.NullTime{Time: util.TestTime, Valid: true},
+			CreatedAt: util.TestTime,
+		}, usr)
+		util.AssertDeepEqual(t, expectedArticles, articles)
+	})
+
+	t.Run("CreateCustom with M2M relation", func(t *testing.T) {
+		usr := models.User{}
+		club := models.Club{}
+		sdr.CreateCustom("Club", seedr.Trait{
+			"users": seedr.CreateRelated("User"),
+		}).Scan(&club).ScanRelated("users", &usr)
+
+		util.AssertDeepEqual(t, club, models.Club{ID: 3, Name: "Club-3"})
+		util.AssertDeepEqual(t, usr, models.User{
+			ID:        13,
+			Email:     "agentsmith-13@gmail.com",
+			Name:      "Agent Smith 13",
+			Active:    true,
+			Checkin:   sql.NullTime{Time: util.TestTime, Valid: true},
+			CreatedAt: util.TestTime,
+		})
+	})
+
+	t.Run("CreateCustom with M2M relation with inline CreateRelatedCustom with child relation", func(t *testing.T) {
+		usr := models.User{}
+		club := models.Club{}
+		article := models.Article{}
+		sdr.CreateCustom("Club", seedr.Trait{
+			"users": seedr.CreateRelatedCustom("User", seedr.Trait{
+				"articles": seedr.CreateRelated("Article"),
+			}),
+		}).Scan(&club).
+			ScanRelated("users", &usr).
+			Related("users").
+			ScanRelated("articles", &article)
+
+		util.AssertDeepEqual(t, club, models.Club{ID: 4, Name: "Club-4"})
+		util.AssertDeepEqual(t, usr, models.User{
+			ID:        14,
+			Email:     "agentsmith-14@gmail.com",
+			Name:      "Agent Smith 14",
+			Active:    true,
+			Checkin:   sql.NullTime{Time: util.TestTime, Valid: true},
+			CreatedAt: util.TestTime,
+		})
+		util.AssertDeepEqual(t, article, models.Article{
+			ID:        12,
+			UserID:    14,
+			Title:     "Awesome Title 12",
+			Body:      "test body value",
+			CreatedAt: util.TestTime,
+		})
+	})
+
+	t.Run("Insert with all fields Auto()", func(t *testing.T) {
+		var visits []models.Visit
+		sdr.CreateBatch("Visit", 2).Scan(&visits)
+		if len(visits) != 2 || visits[0].ID != 1 || visits[1].ID != 2 {
+			t.Fatalf("Expected visits 1 and 2, got %+v", visits)
+		}
+		for _, v := range visits {
+			if v.CreatedAt.IsZero() {
+				t.Errorf("Expected default created_at of visit %d", v.ID)
+			}
+		}
+	})
+
+	t.Run("Build instance", func(t *testing.T) {
+		usr := models.User{}
+		sdr.Build("TestUser").Scan(&usr)
+		util.AssertDeepEqual(t, models.User{
+			ID:        0,
+			Name:      "Agent Smith 15",
+			Email:     "agentsmith-15@gmail.com",
+			Active:    true,
+			Checkin:   sql.NullTime{Time: util.TestTime, Valid: true},
+			CreatedAt: util.TestTime,
+		}, usr)
+	})
+
+}
+
+// RunUnitOfWork checks that failed Create leaves no records in db
+// when Seedr is in unit of work mode. drv must be a driver of db.
+func RunUnitOfWork(t *testing.T, db *sql.DB, drv driver.Driver) {
+	sdr := seedrs.NewTestSeedr(drv, seedr.SetUnitOfWork(true))
+
+	count := func(table string) (n int) {
+		if err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	users, articles := count("users"), count("articles")
+
+	_, err := sdr.TryCreateCustom("UserHeavyWriter", seedr.Trait{
+		"articles": seedr.CreateRelatedCustomBatch("Article", 2, seedr.Trait{
+			"no_such_column": 1,
+		}),
+	})
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+	if users != count("users") || articles != count("articles") {
+		t.Errorf("Expected transaction to be rolled back")
+	}
+
+	sdr.Create("UserHeavyWriter")
+	if users+1 != count("users") || articles+2 != count("articles") {
+		t.Errorf("Expected transaction to be committed")
+	}
+}
+
+// RunSeedrtest checks that records created using seedrtest.Begin
+// are rolled back, including nested (savepoint) transactions of subtests.
+// sdr must use driver of db.
+func RunSeedrtest(t *testing.T, db *sql.DB, sdr *seedr.Seedr) {
+	var users int
+	if err := db.QueryRow("SELECT COUNT(*) FROM users").Scan(&users); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Transaction", func(t *testing.T) {
+		tsdr := seedrtest.Begin(t, sdr)
+		tsdr.CreateBatch("TestUser", 2)
+		t.Run("Savepoint", func(t *testing.T) {
+			seedrtest.Begin(t, tsdr).CreateCustom("TestUser", seedr.Trait{"id": 9999})
+		})
+		// savepoint is rolled back, so id is free again
+		if _, err := tsdr.TryCreateCustom("TestUser", seedr.Trait{"id": 9999}); err != nil {
+			t.Errorf("Expected savepoint to be rolled back: %s", err)
+		}
+	})
+
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM users").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != users {
+		t.Errorf("Expected transaction to be rolled back, got %d new users", n-users)
+	}
+}
+
+// RunSession checks that Session.Cleanup deletes all created records,
+// including join records of M2M relations, but not records found by FindOrCreate.
+// sdr must use driver of db.
+func RunSession(t *testing.T, db *sql.DB, sdr *seedr.Seedr) {
+	tables := []string{"users", "articles", "clubs", "clubs_to_users"}
+	count := func() map[string]int {
+		ret := make(map[string]int)
+		for _, table := range tables {
+			var n int
+			if err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n); err != nil {
+				t.Fatal(err)
+			}
+			ret[table] = n
+		}
+		return ret
+	}
+	var existing models.User
+	sdr.Create("TestUser").Scan(&existing)
+	before := count()
+
+	s := sdr.Session()
+	s.Create("ClubWithUsers")
+	s.Create("UserHeavyWriter")
+	s.CreateBatch("TestArticle", 3)
+	// only new user and its articles are created (and deleted by Cleanup)
+	s.FindOrCreateCustomBatch("UserHeavyWriter", 2, seedr.Trait{
+		"email": seedr.Loop([]string{existing.Email, "session@example.com"}),
+	})
+	if reflect.DeepEqual(before, count()) {
+		t.Fatal("Expected records to be created")
+	}
+
+	if err := s.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	if after := count(); !reflect.DeepEqual(before, after) {
+		t.Errorf("Expected all records to be deleted:\nbefore: %v\nafter:  %v", before, after)
+	}
+}
+
+// RunReload checks that Reload re-reads records and their relations.
+// sdr must use driver of db.
+func RunReload(t *testing.T, db *sql.DB, sdr *seedr.Seedr) {
+	var art models.Article
+	var usr models.User
+	ti := sdr.Create("TestArticle").Scan(&art).ScanRelated("author", &usr)
+
+	if _, err := db.Exec(Rebind("UPDATE articles SET title = 'Reloaded' WHERE id = ?"), art.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(Rebind("UPDATE users SET name = 'Reloaded' WHERE id = ?"), usr.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	ti.Reload().Scan(&art).ScanRelated("author", &usr)
+	if art.Title != "Reloaded" || usr.Name != "Reloaded" {
+		t.Errorf("Expected records to be reloaded, got %q and %q", art.Title, usr.Name)
+	}
+}
+
+// RunUpdate checks that Update and UpdateAll store changes.
+// sdr must use driver of db.
+func RunUpdate(t *testing.T, db *sql.DB, sdr *seedr.Seedr) {
+	var usr models.User
+	sdr.Create("TestUser").Update(seedr.Trait{"active": false}).Scan(&usr)
+	var active bool
+	if err := db.QueryRow(Rebind("SELECT active FROM users WHERE id = ?"), usr.ID).Scan(&active); err != nil {
+		t.Fatal(err)
+	}
+	if active || usr.Active {
+		t.Errorf("Expected user to be deactivated")
+	}
+
+	var users []models.User
+	sdr.CreateBatch("TestUser", 3).UpdateAll(seedr.Trait{
+		"name": seedr.SequenceString("Updated %d"),
+	}).Reload().Scan(&users)
+	for i, u := range users {
+		if expected := fmt.Sprintf("Updated %d", i+1); u.Name != expected {
+			t.Errorf("Expected name %q, got %q", expected, u.Name)
+		}
+	}
+}
+
+// RunUpsert checks that FindOrCreate and Upsert don't create duplicates
+// of users with same email (and their articles) and return real primary keys of existing ones.
+// sdr must use driver of db.
+func RunUpsert(t *testing.T, db *sql.DB, sdr *seedr.Seedr) {
+	var created, found, upserted models.User
+	sdr.FindOrCreateCustom("TestUser", seedr.Trait{"email": "seed@example.com", "name": "Seed"}).Scan(&created)
+	sdr.FindOrCreateCustom("TestUser", seedr.Trait{"email": "seed@example.com", "name": "Changed"}).Scan(&found)
+	if found.ID != created.ID || found.Name != "Seed" {
+		t.Errorf("Expected existing user %d to be found, got %#v", created.ID, found)
+	}
+	sdr.UpsertCustom("TestUser", seedr.Trait{"email": "seed@example.com", "name": "Upserted"}).Scan(&upserted)
+	if upserted.ID != created.ID || upserted.Name != "Upserted" {
+		t.Errorf("Expected existing user %d to be updated, got %#v", created.ID, upserted)
+	}
+	var partial models.User
+	sdr.UpsertCustom("TestUser", seedr.Trait{"email": "seed@example.com", "name": "Partial", "active": !upserted.Active},
+		seedr.UpdateFields("name")).Scan(&partial)
+	if partial.Name != "Partial" || partial.Active != upserted.Active {
+		t.Errorf("Expected only name of user %d to be updated, got %#v", created.ID, partial)
+	}
+
+	var art models.Article
+	var author models.User
+	sdr.FindOrCreateCustom("TestArticle", seedr.Trait{
+		"author": seedr.CreateRelatedCustom("TestUser", seedr.Trait{"email": "seed@example.com"}),
+	}).Scan(&art).ScanRelated("author", &author)
+	if author.ID != created.ID || art.UserID != created.ID {
+		t.Errorf("Expected article of existing user %d, got author %d", created.ID, art.UserID)
+	}
+
+	// articles of existing user are not created again
+	writer := sdr.FindOrCreateCustom("UserHeavyWriter", seedr.Trait{"email": "seed@example.com"})
+	var arts int
+	if err := db.QueryRow(Rebind("SELECT COUNT(*) FROM articles WHERE author_id = ?"), created.ID).Scan(&arts); err != nil {
+		t.Fatal(err)
+	}
+	if writer.Related("articles").Len() != 0 || arts != 1 {
+		t.Errorf("Expected no articles to be created for existing user, got %d stored", arts)
+	}
+
+	var users []models.User
+	sdr.FindOrCreateBatch("TestUser", 3).Scan(&users)
+	var again []models.User
+	sdr.FindOrCreateCustomBatch("TestUser", 3, seedr.Trait{
+		"email": seedr.Loop([]string{users[0].Email, users[1].Email, users[2].Email}),
+	}).Scan(&again)
+	for i := range users {
+		if again[i].ID != users[i].ID {
+			t.Errorf("Expected user %d to be found, got %d", users[i].ID, again[i].ID)
+		}
+	}
+
+	var cnt int
+	if err := db.QueryRow("SELECT COUNT(*) FROM users").Scan(&cnt); err != nil {
+		t.Fatal(err)
+	}
+	if cnt != 4 {
+		t.Errorf("Expected 4 users, got %d", cnt)
+	}
+}
+
+// RunClientKeys checks that records with client-generated primary keys
+// (ULID strings, binary UUIDs and UUID strings in column other than id)
+// are returned and related properly.
+// sdr must use driver of db.
+func RunClientKeys(t *testing.T, db *sql.DB, sdr *seedr.Seedr) {
+	var tag models.Tag
+	var arts []models.Article
+	sdr.Create("TagWithArticles").Scan(&tag).ScanRelated("articles", &arts)
+	if len(tag.ID) != 26 || len(arts) != 2 {
+		t.Fatalf("Expected tag with 2 articles, got %#v, %#v", tag, arts)
+	}
+	var cnt int
+	if err := db.QueryRow(Rebind("SELECT COUNT(*) FROM articles_to_tags WHERE tag_id = ?"), tag.ID).Scan(&cnt); err != nil {
+		t.Fatal(err)
+	}
+	if cnt != 2 {
+		t.Errorf("Expected 2 articles of tag %s, got %d", tag.ID, cnt)
+	}
+
+	var link models.ArticleTag
+	var linkTag models.Tag
+	sdr.Create("ArticleTagWithTag").Scan(&link).ScanRelated("tag", &linkTag)
+	if link.ID == (seedr.BinaryUUID{}) || link.TagID != linkTag.ID || linkTag.ID == "" {
+		t.Errorf("Expected link to its tag, got %#v, %#v", link, linkTag)
+	}
+	var storedTagID string
+	if err := db.QueryRow(Rebind("SELECT tag_id FROM articles_to_tags WHERE id = ?"), link.ID).Scan(&storedTagID); err != nil {
+		t.Fatal(err)
+	}
+	if storedTagID != linkTag.ID {
+		t.Errorf("Expected stored tag %s, got %s", linkTag.ID, storedTagID)
+	}
+
+	var links []models.ArticleTag
+	ti := sdr.CreateBatch("TagWithLinks", 2)
+	for i := 0; i < ti.Len(); i++ {
+		ti.Index(i).Reload().Scan(&tag).ScanRelated("links", &links)
+		if len(links) != 2 || links[0].TagID != tag.ID || links[1].TagID != tag.ID {
+			t.Errorf("Expected 2 links of tag %s, got %#v", tag.ID, links)
+		}
+	}
+
+	// labels have primary key named uuid, join records must refer to it
+	var labels []models.Label
+	sdr.Create("TagWithLabels").Scan(&tag).ScanRelated("labels", &labels)
+	if len(labels) != 2 || len(labels[0].UUID) != 36 {
+		t.Fatalf("Expected tag with 2 labels, got %#v", labels)
+	}
+	for _, l := range labels {
+		if err := db.QueryRow(Rebind("SELECT COUNT(*) FROM tags_to_labels WHERE tag_id = ? AND label_uuid = ?"), tag.ID, l.UUID).Scan(&cnt); err != nil {
+			t.Fatal(err)
+		}
+		if cnt != 1 {
+			t.Errorf("Expected label %s of tag %s, got %d", l.UUID, tag.ID, cnt)
+		}
+	}
+}
+
+// BenchBatchSize is a size of batches in benchmarks.
+const BenchBatchSize = 10000
+
+// BenchmarkInsertBatch creates batches of users.
+func BenchmarkInsertBatch(b *testing.B, sdr *seedr.Seedr) {
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = sdr.CreateBatch("UserJohn", BenchBatchSize)
+	}
+}
+
+// BenchmarkInsertBatchWithRelations creates batches of articles with authors.
+func BenchmarkInsertBatchWithRelations(b *testing.B, sdr *seedr.Seedr) {
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = sdr.CreateBatch("TestArticle", BenchBatchSize)
+	}
+}
+
+// BenchmarkInsert creates single user.
+func BenchmarkInsert(b *testing.B, sdr *seedr.Seedr) {
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = sdr.Create("UserJohn")
+	}
+}
+
+// BenchmarkInsertManyFields creates single record with many fields.
+func BenchmarkInsertManyFields(b *testing.B, sdr *seedr.Seedr) {
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = sdr.Create("HellotaFieldsTest")
+	}
+}
+
+// BenchmarkScanBatch scans batch of users into structs.
+func BenchmarkScanBatch(b *testing.B, sdr *seedr.Seedr) {
+	ins := sdr.CreateBatch("UserJohn", BenchBatchSize)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		var users []models.User
+		ins.Scan(&users)
+	}
+}
+
+// RunParallelBatches creates batches of users with mixed explicit and Auto()
+// primary keys from parallel goroutines and checks that every returned record
+// is the one that was inserted from respective position of payload.
+func RunParallelBatches(t *testing.T, drv driver.Driver) {
+	const goroutines, batches, batchSize = 8, 5, 20
+	// auto-generated keys follow the greatest existing one, so record with key
+	// above all explicit ones is created first, otherwise they may collide
+	_, err := drv.Create(driver.Payload{
+		Entity:       "users",
+		PrimaryKey:   "id",
+		InsertFields: []string{"id", "name", "email"},
+		Data:         []map[string]interface{}{{"id": 1000000, "name": "Parallel", "email": "parallel@gmail.com"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines*batches)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for b := 0; b < batches; b++ {
+				data := make([]map[string]interface{}, batchSize)
+				for i := range data {
+					data[i] = map[string]interface{}{
+						"id":    nil,
+						"name":  "Parallel",
+						"email": fmt.Sprintf("parallel-%d-%d-%d@gmail.com", g, b, i),
+					}
+					if i%5 == 0 {
+						data[i]["id"] = 100000 + g*10000 + b*100 + i
+					}
+				}
+				res, err := drv.Create(driver.Payload{
+					Entity:       "users",
+					PrimaryKey:   "id",
+					InsertFields: []string{"id", "name", "email"},
+					ReturnFields: []string{"id", "email"},
+					Data:         data,
+				})
+				if err != nil {
+					errs <- err
+					return
+				}
+				for i, rec := range res {
+					if str(rec["email"]) != data[i]["email"] {
+						errs <- fmt.Errorf("record %d: expected email %v, got %s", i, data[i]["email"], str(rec["email"]))
+					}
+					if id := data[i]["id"]; id != nil && str(rec["id"]) != str(id) {
+						errs <- fmt.Errorf("record %d: expected id %v, got %s", i, id, str(rec["id"]))
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+// str formats value returned by database driver, which may be []byte.
+func str(v interface{}) string {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+// RunHugeBatch creates batch of records that doesn't fit into single INSERT statement
+// and checks that all records are returned in order of creation.
+func RunHugeBatch(t *testing.T, sdr *seedr.Seedr) {
+	const n = 5000
+	var recs []models.HellotaFields
+	sdr.CreateBatch("HellotaFieldsTest", n).Scan(&recs)
+	if len(recs) != n {
+		t.Fatalf("Expected %d records, got %d", n, len(recs))
+	}
+	for i := 1; i < n; i++ {
+		if recs[i].A != recs[i-1].A+1 {
+			t.Fatalf("Records %d and %d are out of order: %d, %d", i-1, i, recs[i-1].A, recs[i].A)
+		}
+	}
+}
@@ -1,47 +1,55 @@
 package mysql
 
 import (
-	"bytes"
-	"fmt"
-
 	"github.com/josephbuchma/seedr/driver/sql"
 )
 
-type mySqlBuilder struct{}
+// dialect is a MySQL sql.Dialect.
+type dialect struct{}
 
-func (_ mySqlBuilder) SetVar(name string, value interface{}) string {
-	return fmt.Sprintf("\nSET @%s = %v", name, value)
+func (dialect) QuoteIdent(name string) string {
+	return sql.QuoteIdent(name, "`")
 }
 
-func (_ mySqlBuilder) Placeholders(n int) string {
-	s := bytes.Buffer{}
-	s.WriteString("?")
-	for i := 0; i < n-1; i++ {
-		s.WriteString(",?")
-	}
-	return s.String()
+func (dialect) Placeholder(int) string {
+	return "?"
 }
 
-func bsql() *sql.SQLBuilder {
-	return &sql.SQLBuilder{SetVarer: mySqlBuilder{}, Placeholderer: mySqlBuilder{}}
+func (dialect) Returning() bool {
+	return false
 }
 
-const (
-	lastInsertID = "LAST_INSERT_ID()"
-)
+// IgnoreConflict returns ON DUPLICATE KEY UPDATE clause that sets first key
+// column to its own value. MySQL detects conflict by any unique key, so key
+// columns are used only to leave record unchanged. Unchanged record is not
+// counted by RowsAffected, unless CLIENT_FOUND_ROWS flag is set.
+func (d dialect) IgnoreConflict(key []string) string {
+	q := d.QuoteIdent(key[0])
+	return " ON DUPLICATE KEY UPDATE " + q + "=" + q
+}
 
-func insertSQL(table string, insertFields []string) string {
-	return bsql().Insert(table, insertFields, 1).String()
+// LastInsertID is LastInsertIDFirst, so IDs of Auto() records
+// are selected starting from it (see insertAuto).
+func (dialect) LastInsertID() sql.LastInsertID {
+	return sql.LastInsertIDFirst
 }
 
-func insertBatchSQL(n int, table string, insertFields []string) string {
-	return bsql().Insert(table, insertFields, n).String()
+func bsql() *sql.SQLBuilder {
+	return sql.NewBuilder(dialect{})
+}
+
+// snapshotSQL is a consistent read that makes InnoDB take snapshot of transaction
+// (if it's not taken yet), so rows committed by others later are not visible.
+func snapshotSQL(table string) string {
+	return "SELECT 1 FROM " + dialect{}.QuoteIdent(table) + " LIMIT 1"
 }
 
-func selectLastSQL(table, pk string, selectFields []string) string {
-	return bsql().Select(selectFields).From(table).Where(pk).Eql(lastInsertID).String()
+// selectIDsFromSQL selects limited number of primary keys starting from given one.
+func selectIDsFromSQL(table, pk string) string {
+	q := dialect{}.QuoteIdent(pk)
+	return bsql().Select([]string{pk}).From(table).String() + " WHERE " + q + ">=? ORDER BY " + q + " LIMIT ?"
 }
 
-func selectBetweenSQL(table, pk string, fields []string, a, b interface{}) string {
-	return bsql().Select(fields).From(table).Where(pk).Between(a).And(b).String()
+func insertBatchSQL(n int, table string, insertFields []string) string {
+	return bsql().Insert(table, insertFields, n).String()
 }
@@ -0,0 +1,27 @@
+package mysql
+
+import "testing"
+
+func TestDialect(t *testing.T) {
+	testCases := []struct {
+		got, expected string
+	}{
+		{
+			insertBatchSQL(2, "order", []string{"key", "name"}),
+			"\nINSERT INTO `order` (`key`, `name`) VALUES \n(?,?),\n(?,?)",
+		},
+		{
+			bsql().Select([]string{"key"}).From("db.order").Where("id").In(2).String(),
+			"\nSELECT `key` FROM `db`.`order` WHERE `id` IN (?,?)",
+		},
+		{
+			bsql().Insert("users", []string{"email"}, 1).IgnoreConflict([]string{"email"}).String(),
+			"\nINSERT INTO `users` (`email`) VALUES (?) ON DUPLICATE KEY UPDATE `email`=`email`",
+		},
+	}
+	for _, tc := range testCases {
+		if tc.got != tc.expected {
+			t.Errorf("Expected:\n%q\ngot:\n%q", tc.expected, tc.got)
+		}
+	}
+}
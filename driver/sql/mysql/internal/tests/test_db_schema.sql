@@ -11,7 +11,8 @@ create table users (
     active     boolean default false,
     checkin    datetime,
     created_at datetime not null default NOW(),
-    primary key (id)
+    primary key (id),
+    unique key users_email (email)
 ) engine=InnoDB default charset=utf8;
 
 drop table if exists articles;
@@ -42,6 +43,40 @@ create table clubs_to_users (
     primary key (id)
 ) engine=InnoDB default charset=utf8;
 
+drop table if exists tags;
+create table tags (
+    id          char(26) not null,
+    name        varchar(250) not null,
+
+    primary key (id)
+) engine=InnoDB default charset=utf8;
+
+drop table if exists articles_to_tags;
+create table articles_to_tags (
+    id          binary(16) not null,
+    article_id  int(10) unsigned not null,
+    tag_id      char(26) not null,
+
+    primary key (id)
+) engine=InnoDB default charset=utf8;
+
+
+drop table if exists labels;
+create table labels (
+    uuid        char(36) not null,
+    name        varchar(250) not null,
+
+    primary key (uuid)
+) engine=InnoDB default charset=utf8;
+
+drop table if exists tags_to_labels;
+create table tags_to_labels (
+    id          int(10) unsigned not null auto_increment,
+    tag_id      char(26) not null,
+    label_uuid  char(36) not null,
+
+    primary key (id)
+) engine=InnoDB default charset=utf8;
 
 drop table if exists hellota_fields;
 create table hellota_fields (
@@ -74,3 +109,10 @@ create table hellota_fields (
     z int(10) unsigned,
     primary key (id)
 ) engine=InnoDB default charset=utf8;
+
+drop table if exists visits;
+create table visits (
+    id int(10) unsigned not null auto_increment,
+    created_at datetime not null default NOW(),
+    primary key (id)
+) engine=InnoDB default charset=utf8;
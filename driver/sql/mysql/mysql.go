@@ -1,49 +1,83 @@
 // Package mysql is a MySQL driver for Seedr.
 // Entity (which represents table name in this case) and PrimaryKey must be specified for each Factory.
+// Primary key may be auto-increment integer (Auto()), or value of any type
+// given explicitly (e.g. BINARY(16) UUID generated by seedr.UUID()).
+// If PrimaryKey is not provided, no results are returned by Create.
+// Upsert finds out whether record exists by affected rows of INSERT ... ON DUPLICATE KEY UPDATE,
+// so CLIENT_FOUND_ROWS flag must not be set (e.g. clientFoundRows of go-sql-driver/mysql DSN).
 package mysql
 
 import (
-	"database/sql"
+	"context"
+	gosql "database/sql"
+	sqldriver "database/sql/driver"
 	"errors"
+	"fmt"
 
 	"github.com/josephbuchma/seedr/driver"
+	seedrsql "github.com/josephbuchma/seedr/driver/sql"
 )
 
-func panicOnError(err error) {
-	if err != nil {
-		panic(err)
-	}
-}
+// maxChunk is a max number of records deleted or fetched by single statement
+const maxChunk = 1000
 
 // MySQL driver for Seedr
 type MySQL struct {
-	db *sql.DB
+	*seedrsql.Driver
+	limits
 }
 
-// New creates new Driver.
-func New(db *sql.DB) driver.Driver {
-	return &MySQL{db}
+// limits define max size of single INSERT statement.
+type limits struct {
+	maxPlaceholders int
+	maxPacketSize   int
 }
 
-// Create inserts payload Data into database and returns inserted records
-// Entity is a table name. If PrimaryKey is not provided, no results will be returned.
-func (my *MySQL) Create(p driver.Payload) (results []map[string]interface{}, err error) {
-	tx, err := my.db.Begin()
-	if err != nil {
-		return nil, err
+// Option configures MySQL driver.
+type Option func(*MySQL)
+
+// SetMaxPlaceholders sets max number of placeholders in single INSERT statement.
+// Default is 65535, which is a limit of MySQL prepared statements.
+func SetMaxPlaceholders(n int) Option {
+	if n < 1 {
+		panic("max placeholders must be positive")
 	}
-	d := drv{tx}
-	ret, err := d.insert(insertPayload{p.Entity, p.PrimaryKey, p.InsertFields, p.ReturnFields, p.Data})
-	if err != nil {
-		tx.Rollback()
-		return nil, err
+	return func(my *MySQL) {
+		my.maxPlaceholders = n
+	}
+}
+
+// SetMaxPacketSize sets approximate max size (in bytes) of single INSERT statement
+// together with its values. It must not exceed max_allowed_packet of MySQL server.
+// Default is 4MiB, which is a default max_allowed_packet of MySQL 5.7.
+func SetMaxPacketSize(n int) Option {
+	if n < 1 {
+		panic("max packet size must be positive")
+	}
+	return func(my *MySQL) {
+		my.maxPacketSize = n
 	}
-	err = tx.Commit()
-	return ret, err
+}
+
+// New creates new Driver (see sql.NewDriver). Big batches are inserted
+// in chunks (see SetMaxPlaceholders and SetMaxPacketSize) within single transaction.
+func New(db seedrsql.DB, opts ...Option) driver.Driver {
+	my := &MySQL{limits: limits{maxPlaceholders: 65535, maxPacketSize: 4 << 20}}
+	for _, opt := range opts {
+		opt(my)
+	}
+	my.Driver = seedrsql.NewDriver(db, dialect{}, maxChunk, my.insert)
+	return my
+}
+
+func (my *MySQL) insert(ctx context.Context, db seedrsql.DB, p driver.Payload) ([]map[string]interface{}, error) {
+	return drv{ctx, db, my.limits}.insert(insertPayload{p.Entity, p.PrimaryKey, p.InsertFields, p.ReturnFields, p.Data})
 }
 
 type drv struct {
-	db *sql.Tx
+	ctx context.Context
+	db  seedrsql.DB
+	limits
 }
 
 type insertPayload struct {
@@ -52,124 +86,185 @@ type insertPayload struct {
 	data                       []map[string]interface{}
 }
 
-func (ip insertPayload) AllValues() []interface{} {
-	sls := make([]interface{}, len(ip.insertFields)*len(ip.data))
-	i := 0
-	for _, d := range ip.data {
-		for _, f := range ip.insertFields {
-			sls[i] = d[f]
-			i++
+// values returns values of given fields of all records.
+func values(fields []string, data []map[string]interface{}) []interface{} {
+	sls := make([]interface{}, 0, len(fields)*len(data))
+	for _, d := range data {
+		for _, f := range fields {
+			sls = append(sls, d[f])
 		}
 	}
 	return sls
 }
 
-func makePtrs(v []interface{}) []interface{} {
-	ptrs := make([]interface{}, len(v))
-	for i := range v {
-		ptrs[i] = &v[i]
+func without(fields []string, field string) []string {
+	ret := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f != field {
+			ret = append(ret, f)
+		}
 	}
-	return ptrs
-}
-
-func (my drv) queryRowScan(sql string, vals []interface{}, result []interface{}) {
-	panicOnError(my.db.QueryRow(sql, vals...).Scan(makePtrs(result)...))
+	return ret
 }
 
-func (my drv) exec(sql string, vals []interface{}) error {
-	_, err := my.db.Exec(sql, vals...)
-	return err
-}
-
-func (my drv) query(sql string, vals []interface{}, results []interface{}) error {
-	rows, err := my.db.Query(sql, vals...)
-	if err != nil {
-		return err
+// chunks splits data into chunks that can be inserted by single statement
+// without exceeding limits. Every chunk contains at least one record.
+func (l limits) chunks(table string, fields []string, data []map[string]interface{}) [][]map[string]interface{} {
+	header := len(table) + 32
+	for _, f := range fields {
+		header += len(f) + 2
 	}
-	defer rows.Close()
-
-	cols, err := rows.Columns()
-	if err != nil {
-		return err
+	var ret [][]map[string]interface{}
+	b, size := 0, header
+	for i, rec := range data {
+		rs := rowSize(fields, rec)
+		if i > b && ((i-b+1)*len(fields) > l.maxPlaceholders || size+rs > l.maxPacketSize) {
+			ret = append(ret, data[b:i])
+			b, size = i, header
+		}
+		size += rs
 	}
+	return append(ret, data[b:])
+}
 
-	shift := 0
-	for rows.Next() {
-		r := results[shift : shift+len(cols)]
-		err := rows.Scan(makePtrs(r)...)
-		if err != nil {
-			return err
+// rowSize estimates size of record in INSERT statement (placeholders and values).
+func rowSize(fields []string, rec map[string]interface{}) int {
+	size := 2*len(fields) + 3
+	for _, f := range fields {
+		// every value has type and length overhead in packet
+		size += 9
+		v := rec[f]
+		if vr, ok := v.(sqldriver.Valuer); ok {
+			v, _ = vr.Value()
+		}
+		switch v := v.(type) {
+		case string:
+			size += len(v)
+		case []byte:
+			size += len(v)
 		}
-		shift += len(cols)
 	}
-	return rows.Err()
+	return size
 }
 
-func (my *drv) insert(ins insertPayload) ([]map[string]interface{}, error) {
-	inserted, err := my.insertRows(len(ins.data), ins.table, ins.pk, ins.insertFields, ins.returnFields, ins.AllValues())
-	if err != nil {
-		return nil, err
-	}
-	ret := make([]map[string]interface{}, 0, len(ins.data))
-	for i := 0; i < len(inserted); {
-		r := make(map[string]interface{})
-		for _, f := range ins.returnFields {
-			r[f] = inserted[i]
-			i++
+// insertChunks inserts data by multi-row INSERT statements that fit into limits,
+// and calls fn (if not nil) with result of every statement and number of records inserted by it.
+func (my drv) insertChunks(table string, fields []string, data []map[string]interface{}, fn func(res gosql.Result, n int) error) error {
+	for _, chunk := range my.chunks(table, fields, data) {
+		res, err := my.exec(insertBatchSQL(len(chunk), table, fields), values(fields, chunk))
+		if err != nil {
+			return err
+		}
+		if fn != nil {
+			if err := fn(res, len(chunk)); err != nil {
+				return err
+			}
 		}
-		ret = append(ret, r)
 	}
+	return nil
+}
 
-	return ret, nil
+func (my drv) exec(sql string, vals []interface{}) (gosql.Result, error) {
+	return my.db.ExecContext(my.ctx, sql, vals...)
 }
 
-func (my *drv) insertRows(n int, table, pk string, insertFields, selectFields []string, vals []interface{}) ([]interface{}, error) {
-	var firstRecordID int64
-	var err error
-	if len(vals) == 0 || n == 0 {
+// insert inserts records and returns their ReturnFields in input order.
+// Records with explicit primary key values and records with Auto() primary key
+// are inserted separately, so IDs assigned by MySQL are known exactly
+// (see insertAuto). Then all records are selected by list of primary keys.
+func (my drv) insert(ins insertPayload) ([]map[string]interface{}, error) {
+	if len(ins.data) == 0 {
 		return nil, errors.New("Nothing to create")
 	}
-	if len(vals)/n != len(insertFields) {
-		// must be unreachable
-		panic("INVALID LENGTH OF VALS")
+	if ins.pk == "" {
+		return nil, my.insertChunks(ins.table, ins.insertFields, ins.data, nil)
 	}
-	ret := make([]interface{}, n*len(selectFields))
 
-	// Insert and fetch first record
-	s := insertSQL(table, insertFields)
-	if err := my.exec(s, vals[0:len(insertFields)]); err != nil {
-		return nil, err
-	}
-	if pk == "" {
-		return nil, nil
+	var explicit, auto []map[string]interface{}
+	var autoIdx []int
+	pks := make([]map[string]interface{}, len(ins.data))
+	for i, rec := range ins.data {
+		if rec[ins.pk] == nil {
+			auto = append(auto, rec)
+			autoIdx = append(autoIdx, i)
+			continue
+		}
+		explicit = append(explicit, rec)
+		pks[i] = map[string]interface{}{ins.pk: rec[ins.pk]}
 	}
-	if n == 1 {
-		s = selectLastSQL(table, pk, selectFields)
-		err := my.query(s, nil, ret[0:len(selectFields)])
-		return ret, err
+	if len(explicit) > 0 {
+		if err := my.insertChunks(ins.table, ins.insertFields, explicit, nil); err != nil {
+			return nil, err
+		}
 	}
-	s = selectLastSQL(table, pk, []string{pk})
-	err = my.db.QueryRow(s).Scan(&firstRecordID)
-	if err != nil {
-		return nil, err
+	if len(auto) > 0 {
+		ids, err := my.insertAuto(ins.table, ins.pk, without(ins.insertFields, ins.pk), auto)
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range autoIdx {
+			pks[i] = map[string]interface{}{ins.pk: ids[j]}
+		}
 	}
 
-	s = insertBatchSQL(n-1, table, insertFields)
-	err = my.exec(s, vals[len(insertFields):])
-	if err != nil {
-		return nil, err
+	if len(ins.returnFields) == 0 {
+		ret := make([]map[string]interface{}, len(ins.data))
+		for i := range ret {
+			ret[i] = make(map[string]interface{})
+		}
+		return ret, nil
 	}
-
-	s = selectBetweenSQL(table, pk, selectFields, firstRecordID, int(firstRecordID)+len(vals))
-	err = my.query(s, nil, ret)
-	return ret, err
+	return seedrsql.Find(my.ctx, my.db, driver.Payload{
+		Entity:       ins.table,
+		PrimaryKey:   ins.pk,
+		ReturnFields: ins.returnFields,
+		Data:         pks,
+	}, maxChunk, dialect{})
 }
 
-func indexOfStr(str string, s []string) int {
-	for i, v := range s {
-		if v == str {
-			return i
-		}
+// insertAuto inserts records with auto-increment primary key by multi-row INSERTs
+// (see insertChunks) and returns their IDs.
+// IDs of rows inserted by single statement are increasing, but they may be not
+// consecutive (e.g. if innodb_autoinc_lock_mode is 2), because rows inserted
+// concurrently by other transactions may interleave with them.
+// So consistent snapshot is taken before insert, and then IDs are selected starting
+// from sql.Result.LastInsertId: interleaved rows are not visible in that snapshot.
+// It requires REPEATABLE READ isolation level (InnoDB default), in other levels
+// error is returned if interleaved rows are found.
+func (my drv) insertAuto(table, pk string, fields []string, data []map[string]interface{}) ([]int64, error) {
+	var one int
+	if err := my.db.QueryRowContext(my.ctx, snapshotSQL(table)).Scan(&one); err != nil && err != gosql.ErrNoRows {
+		return nil, err
 	}
-	panic("failed to find string in slice")
+	ids := make([]int64, 0, len(data))
+	s := selectIDsFromSQL(table, pk)
+	err := my.insertChunks(table, fields, data, func(res gosql.Result, n int) error {
+		first, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		rows, err := my.db.QueryContext(my.ctx, s, first, n+1)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		found := 0
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			ids = append(ids, id)
+			found++
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if found != n {
+			return fmt.Errorf("%d records inserted into %s, but %d found starting from id %d "+
+				"(transaction isolation level must be REPEATABLE READ)", n, table, found, first)
+		}
+		return nil
+	})
+	return ids, err
 }
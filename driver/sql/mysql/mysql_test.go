@@ -1 +1,219 @@
 package mysql
+
+import (
+	sqldriver "database/sql/driver"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/josephbuchma/seedr/driver"
+	"github.com/josephbuchma/seedr/driver/sql/internal/fakedb"
+)
+
+// fakeUsers emulates users (id, name) table with auto-increment id.
+// If foreign is set, row of other transaction is inserted after each
+// of Auto() rows, it's visible only if committed is set.
+type fakeUsers struct {
+	foreign, committed bool
+
+	mu     sync.Mutex
+	next   int64
+	rows   map[int64]string
+	hidden map[int64]bool
+}
+
+func (u *fakeUsers) handle(query string, args []sqldriver.Value) fakedb.Result {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.rows == nil {
+		u.rows = make(map[int64]string)
+		u.hidden = make(map[int64]bool)
+		u.next = 1
+	}
+	switch {
+	case query == snapshotSQL("users"):
+		return fakedb.Result{Columns: []string{"1"}}
+	case strings.Contains(query, "INSERT INTO `users` (`id`, `name`)"):
+		for i := 0; i < len(args); i += 2 {
+			id := args[i].(int64)
+			u.rows[id] = args[i+1].(string)
+			if id >= u.next {
+				u.next = id + 1
+			}
+		}
+		return fakedb.Result{RowsAffected: int64(len(args) / 2)}
+	case strings.Contains(query, "INSERT INTO `users` (`name`)"):
+		first := u.next
+		for _, a := range args {
+			u.rows[u.next] = a.(string)
+			u.next++
+			if u.foreign {
+				u.rows[u.next] = "foreign"
+				u.hidden[u.next] = !u.committed
+				u.next++
+			}
+		}
+		return fakedb.Result{LastInsertID: first, RowsAffected: int64(len(args))}
+	case query == selectIDsFromSQL("users", "id"):
+		var rows [][]sqldriver.Value
+		for id := args[0].(int64); id < u.next && int64(len(rows)) < args[1].(int64); id++ {
+			if _, ok := u.rows[id]; ok && !u.hidden[id] {
+				rows = append(rows, []sqldriver.Value{id})
+			}
+		}
+		return fakedb.Result{Columns: []string{"id"}, Rows: rows}
+	case strings.Contains(query, "SELECT `id`, `name` FROM `users`"):
+		// rows are returned in reverse order to check that results are reordered
+		var rows [][]sqldriver.Value
+		for i := len(args) - 1; i >= 0; i-- {
+			id := args[i].(int64)
+			if name, ok := u.rows[id]; ok {
+				rows = append(rows, []sqldriver.Value{id, name})
+			}
+		}
+		return fakedb.Result{Columns: []string{"id", "name"}, Rows: rows}
+	}
+	return fakedb.Result{}
+}
+
+func TestCreateIDs(t *testing.T) {
+	data := []map[string]interface{}{
+		{"id": nil, "name": "Jon"},
+		{"id": int64(3), "name": "Arya"},
+		{"id": nil, "name": "Sansa"},
+		{"id": nil, "name": "Bran"},
+	}
+	testCases := []struct {
+		name     string
+		users    *fakeUsers
+		expected []int64
+	}{
+		{"consecutive", &fakeUsers{}, []int64{4, 3, 5, 6}},
+		{"interleaved", &fakeUsers{foreign: true}, []int64{4, 3, 6, 8}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, log := fakedb.Open(tc.users.handle)
+			defer db.Close()
+
+			res, err := New(db).Create(driver.Payload{
+				Entity:       "users",
+				PrimaryKey:   "id",
+				InsertFields: []string{"id", "name"},
+				ReturnFields: []string{"id", "name"},
+				Data:         data,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := make([]map[string]interface{}, len(data))
+			for i, id := range tc.expected {
+				expected[i] = map[string]interface{}{"id": id, "name": data[i]["name"]}
+			}
+			if !reflect.DeepEqual(expected, res) {
+				t.Errorf("Expected:\n%#v\ngot:\n%#v", expected, res)
+			}
+
+			autoInserts := 0
+			for _, q := range log.Queries() {
+				if strings.Contains(q, "INSERT INTO `users` (`name`)") {
+					autoInserts++
+				}
+			}
+			if autoInserts != 1 {
+				t.Errorf("Expected single insert of Auto() records, got %q", log.Queries())
+			}
+		})
+	}
+
+	// interleaved rows are visible if isolation level is not REPEATABLE READ
+	db, log := fakedb.Open((&fakeUsers{foreign: true, committed: true}).handle)
+	defer db.Close()
+	_, err := New(db).Create(driver.Payload{
+		Entity:       "users",
+		PrimaryKey:   "id",
+		InsertFields: []string{"id", "name"},
+		ReturnFields: []string{"id", "name"},
+		Data:         data,
+	})
+	if err == nil {
+		t.Error("Expected error for visible interleaved rows")
+	}
+	if qs := log.Queries(); qs[len(qs)-1] != "ROLLBACK" {
+		t.Errorf("Expected rollback, got %q", qs)
+	}
+}
+
+func TestChunks(t *testing.T) {
+	data := []map[string]interface{}{
+		{"a": "1234567890", "b": 1},
+		{"a": "1234567890", "b": 2},
+		{"a": "1234567890", "b": 3},
+		{"a": "1234567890", "b": 4},
+		{"a": "1234567890", "b": 5},
+	}
+	header := len("t") + 32 + 2*(1+2)
+	row := rowSize([]string{"a", "b"}, data[0])
+	testCases := []struct {
+		name     string
+		limits   limits
+		expected []int
+	}{
+		{"no limits", limits{65535, 4 << 20}, []int{5}},
+		{"placeholders", limits{4, 4 << 20}, []int{2, 2, 1}},
+		{"packet size", limits{65535, header + 3*row}, []int{3, 2}},
+		{"at least one record", limits{1, 1}, []int{1, 1, 1, 1, 1}},
+	}
+	for _, tc := range testCases {
+		var got []int
+		for _, ch := range tc.limits.chunks("t", []string{"a", "b"}, data) {
+			got = append(got, len(ch))
+		}
+		if !reflect.DeepEqual(tc.expected, got) {
+			t.Errorf("%s: expected chunks %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestCreateChunks(t *testing.T) {
+	users := &fakeUsers{foreign: true}
+	db, log := fakedb.Open(users.handle)
+	defer db.Close()
+
+	names := []string{"Jon", "Arya", "Sansa", "Bran", "Rickon"}
+	data := make([]map[string]interface{}, len(names))
+	expected := make([]map[string]interface{}, len(names))
+	for i, n := range names {
+		data[i] = map[string]interface{}{"name": n}
+		// every other id is taken by interleaved row of other transaction
+		expected[i] = map[string]interface{}{"id": int64(2*i + 1), "name": n}
+	}
+	res, err := New(db, SetMaxPlaceholders(2)).Create(driver.Payload{
+		Entity:       "users",
+		PrimaryKey:   "id",
+		InsertFields: []string{"name"},
+		ReturnFields: []string{"id", "name"},
+		Data:         data,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("Expected:\n%#v\ngot:\n%#v", expected, res)
+	}
+
+	qs := log.Queries()
+	inserts := 0
+	for _, q := range qs {
+		if strings.Contains(q, "INSERT INTO `users` (`name`)") {
+			inserts++
+		}
+	}
+	if inserts != 3 {
+		t.Errorf("Expected 3 chunks, got %q", qs)
+	}
+	if qs[0] != "BEGIN" || qs[len(qs)-1] != "COMMIT" {
+		t.Errorf("Expected all chunks in single transaction, got %q", qs)
+	}
+}
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"strconv"
+
+	"github.com/josephbuchma/seedr/driver/sql"
+)
+
+// dialect is a PostgreSQL sql.Dialect. It yields numbered placeholders ($1, $2, ...),
+// numbering continues across rows of single statement.
+type dialect struct{}
+
+func (dialect) QuoteIdent(name string) string {
+	return sql.QuoteIdent(name, `"`)
+}
+
+func (dialect) Placeholder(i int) string {
+	return "$" + strconv.Itoa(i)
+}
+
+func (dialect) Returning() bool {
+	return true
+}
+
+func (d dialect) IgnoreConflict(key []string) string {
+	return sql.OnConflict(d, key)
+}
+
+func (dialect) LastInsertID() sql.LastInsertID {
+	return sql.LastInsertIDReturning
+}
+
+func bsql() *sql.SQLBuilder {
+	return sql.NewBuilder(dialect{})
+}
+
+func insertReturningSQL(n int, table string, insertFields, returnFields []string) string {
+	return bsql().Insert(table, insertFields, n).Returning(returnFields).String()
+}
+
+func insertDefaultSQL(table string, returnFields []string) string {
+	return bsql().InsertDefault(table).Returning(returnFields).String()
+}
@@ -0,0 +1,27 @@
+package postgres
+
+import "testing"
+
+func TestDialect(t *testing.T) {
+	testCases := []struct {
+		got, expected string
+	}{
+		{
+			bsql().Update("order", []string{"key", "name"}).WhereEq([]string{"id"}).String(),
+			"\nUPDATE \"order\" SET \"key\"=$1, \"name\"=$2 WHERE \"id\"=$3",
+		},
+		{
+			bsql().Select([]string{"key"}).From("public.order").Where("id").In(2).String(),
+			"\nSELECT \"key\" FROM \"public\".\"order\" WHERE \"id\" IN ($1,$2)",
+		},
+		{
+			bsql().Insert("users", []string{"email", "name"}, 2).IgnoreConflict([]string{"email"}).Returning([]string{"id"}).String(),
+			"\nINSERT INTO \"users\" (\"email\", \"name\") VALUES \n($1,$2),\n($3,$4) ON CONFLICT (\"email\") DO NOTHING RETURNING \"id\"",
+		},
+	}
+	for _, tc := range testCases {
+		if tc.got != tc.expected {
+			t.Errorf("Expected:\n%q\ngot:\n%q", tc.expected, tc.got)
+		}
+	}
+}
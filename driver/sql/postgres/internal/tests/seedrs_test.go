@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"database/sql"
+	"flag"
+	"os"
+	"testing"
+
+	_ "github.com/lib/pq"
+
+	sqltests "github.com/josephbuchma/seedr/driver/sql/internal/tests"
+	"github.com/josephbuchma/seedr/driver/sql/internal/tests/seedrs"
+	"github.com/josephbuchma/seedr/driver/sql/postgres"
+)
+
+var schemaSQL = flag.String("schema", "test_db_schema.sql", "test db schema")
+
+// dsn of test database, it can be changed by SEEDR_POSTGRES_DSN environment variable.
+var dsn = "postgres://postgres@127.0.0.1/seedr_test?sslmode=disable"
+
+var testDB = openTestDB()
+
+func openTestDB() *sql.DB {
+	if v := os.Getenv("SEEDR_POSTGRES_DSN"); v != "" {
+		dsn = v
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		panic(err)
+	}
+	return db
+}
+
+func cleanDB() {
+	schema, err := os.ReadFile(*schemaSQL)
+	if err != nil {
+		panic(err)
+	}
+	if _, err := testDB.Exec(string(schema)); err != nil {
+		panic(err)
+	}
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	sqltests.Rebind = sqltests.DollarPlaceholders
+	os.Exit(m.Run())
+}
+
+var sdr = seedrs.NewTestSeedr(postgres.New(testDB))
+
+func TestSeedrs(t *testing.T) {
+	cleanDB()
+	sqltests.Run(t, sdr)
+}
+
+func TestUnitOfWork(t *testing.T) {
+	cleanDB()
+	sqltests.RunUnitOfWork(t, testDB, postgres.New(testDB))
+}
+
+func TestSeedrtest(t *testing.T) {
+	cleanDB()
+	sqltests.RunSeedrtest(t, testDB, sdr)
+}
+
+func TestSession(t *testing.T) {
+	cleanDB()
+	sqltests.RunSession(t, testDB, sdr)
+}
+
+func TestReload(t *testing.T) {
+	cleanDB()
+	sqltests.RunReload(t, testDB, sdr)
+}
+
+func TestUpdate(t *testing.T) {
+	cleanDB()
+	sqltests.RunUpdate(t, testDB, sdr)
+}
+
+func TestUpsert(t *testing.T) {
+	cleanDB()
+	sqltests.RunUpsert(t, testDB, sdr)
+}
+
+func TestClientKeys(t *testing.T) {
+	cleanDB()
+	sqltests.RunClientKeys(t, testDB, sdr)
+}
+
+func TestParallelBatches(t *testing.T) {
+	cleanDB()
+	sqltests.RunParallelBatches(t, postgres.New(testDB))
+}
+
+func TestHugeBatch(t *testing.T) {
+	cleanDB()
+	sqltests.RunHugeBatch(t, sdr)
+}
+
+func BenchmarkInsertBatch(b *testing.B) {
+	cleanDB()
+	sqltests.BenchmarkInsertBatch(b, sdr)
+}
+
+func BenchmarkInsertBatchWithRelations(b *testing.B) {
+	cleanDB()
+	sqltests.BenchmarkInsertBatchWithRelations(b, sdr)
+}
+
+func BenchmarkInsert(b *testing.B) {
+	cleanDB()
+	sqltests.BenchmarkInsert(b, sdr)
+}
+
+func BenchmarkInsertManyFields(b *testing.B) {
+	cleanDB()
+	sqltests.BenchmarkInsertManyFields(b, sdr)
+}
+
+func BenchmarkScanBatch(b *testing.B) {
+	cleanDB()
+	sqltests.BenchmarkScanBatch(b, sdr)
+}
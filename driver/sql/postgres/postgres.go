@@ -0,0 +1,157 @@
+// Package postgres is a PostgreSQL driver for Seedr.
+// Entity (which represents table name in this case) must be specified for each Factory.
+// Inserted records are fetched back using INSERT ... RETURNING,
+// so PrimaryKey is only required for relations.
+// Records are inserted by multi-row INSERT, and RETURNING rows are matched
+// with them by position, because PostgreSQL returns them in order of VALUES.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/josephbuchma/seedr/driver"
+	seedrsql "github.com/josephbuchma/seedr/driver/sql"
+)
+
+// maxChunk is a max number of records deleted or fetched by single statement
+const maxChunk = 1000
+
+// maxParams is a max number of bind parameters of single statement
+const maxParams = 65535
+
+// Postgres driver for Seedr
+type Postgres struct {
+	*seedrsql.Driver
+	// maxParams limits number of records inserted by single statement
+	maxParams int
+}
+
+// New creates new Driver (see sql.NewDriver).
+func New(db seedrsql.DB) driver.Driver {
+	return newPostgres(db, maxParams)
+}
+
+func newPostgres(db seedrsql.DB, maxParams int) *Postgres {
+	pg := &Postgres{maxParams: maxParams}
+	pg.Driver = seedrsql.NewDriver(db, dialect{}, maxChunk, pg.insert)
+	return pg
+}
+
+func (pg *Postgres) insert(ctx context.Context, db seedrsql.DB, p driver.Payload) ([]map[string]interface{}, error) {
+	return drv{ctx, db, pg.maxParams}.insert(insertPayload{p.Entity, p.InsertFields, p.ReturnFields, p.Data})
+}
+
+type drv struct {
+	ctx       context.Context
+	db        seedrsql.DB
+	maxParams int
+}
+
+type insertPayload struct {
+	table                      string
+	insertFields, returnFields []string
+	data                       []map[string]interface{}
+}
+
+func (ip insertPayload) values(data []map[string]interface{}) []interface{} {
+	sls := make([]interface{}, len(ip.insertFields)*len(data))
+	i := 0
+	for _, d := range data {
+		for _, f := range ip.insertFields {
+			sls[i] = d[f]
+			i++
+		}
+	}
+	return sls
+}
+
+func makePtrs(v []interface{}) []interface{} {
+	ptrs := make([]interface{}, len(v))
+	for i := range v {
+		ptrs[i] = &v[i]
+	}
+	return ptrs
+}
+
+// insert inserts records in chunks that fit into maxParams.
+// If there are no fields to insert (e.g. all of them are Auto()),
+// records are inserted one by one with default values.
+//
+// RETURNING rows of multi-row INSERT ... VALUES come in order of VALUES,
+// so i-th returned row belongs to i-th record. Unlike SQLite, where this order
+// is explicitly unspecified, PostgreSQL executes such INSERT as a plan that
+// scans VALUES list in order and inserts rows one at a time, emitting each
+// RETURNING row right after its insertion. Data-modifying statements are never
+// executed in parallel, so nothing can reorder them (ORMs like Django rely on
+// this too). Rows skipped by triggers would break this matching, so number of
+// returned rows is checked (see query).
+func (pg drv) insert(ins insertPayload) ([]map[string]interface{}, error) {
+	if len(ins.data) == 0 {
+		return nil, errors.New("Nothing to create")
+	}
+	if len(ins.insertFields) == 0 {
+		s := insertDefaultSQL(ins.table, ins.returnFields)
+		var ret []map[string]interface{}
+		for range ins.data {
+			recs, err := pg.query(s, nil, ins.returnFields, 1)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, recs...)
+		}
+		return ret, nil
+	}
+	chunk := pg.maxParams / len(ins.insertFields)
+	if chunk == 0 {
+		chunk = 1
+	}
+	var ret []map[string]interface{}
+	for b := 0; b < len(ins.data); b += chunk {
+		e := b + chunk
+		if e > len(ins.data) {
+			e = len(ins.data)
+		}
+		s := insertReturningSQL(e-b, ins.table, ins.insertFields, ins.returnFields)
+		recs, err := pg.query(s, ins.values(ins.data[b:e]), ins.returnFields, e-b)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, recs...)
+	}
+	return ret, nil
+}
+
+// query executes INSERT statement of n records and returns its RETURNING rows.
+func (pg drv) query(s string, vals []interface{}, returnFields []string, n int) ([]map[string]interface{}, error) {
+	if len(returnFields) == 0 {
+		_, err := pg.db.ExecContext(pg.ctx, s, vals...)
+		return nil, err
+	}
+	rows, err := pg.db.QueryContext(pg.ctx, s, vals...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := make([]map[string]interface{}, 0, n)
+	for rows.Next() {
+		vals := make([]interface{}, len(returnFields))
+		if err := rows.Scan(makePtrs(vals)...); err != nil {
+			return nil, err
+		}
+		r := make(map[string]interface{}, len(vals))
+		for i, f := range returnFields {
+			r[f] = vals[i]
+		}
+		ret = append(ret, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ret) != n {
+		return nil, fmt.Errorf("%d records inserted, but %d returned", n, len(ret))
+	}
+	return ret, nil
+}
@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	sqldriver "database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/josephbuchma/seedr/driver"
+	"github.com/josephbuchma/seedr/driver/sql/internal/fakedb"
+)
+
+func TestInsertReturningSQL(t *testing.T) {
+	testCases := []struct {
+		n                          int
+		insertFields, returnFields []string
+		expected                   string
+	}{
+		{
+			1,
+			[]string{"name", "email"},
+			[]string{"name", "email", "id"},
+			"\nINSERT INTO \"users\" (\"name\", \"email\") VALUES ($1,$2) RETURNING \"name\", \"email\", \"id\"",
+		},
+		{
+			3,
+			[]string{"name", "email"},
+			[]string{"name", "email", "id"},
+			"\nINSERT INTO \"users\" (\"name\", \"email\") VALUES \n($1,$2),\n($3,$4),\n($5,$6) RETURNING \"name\", \"email\", \"id\"",
+		},
+	}
+	for _, tc := range testCases {
+		got := insertReturningSQL(tc.n, "users", tc.insertFields, tc.returnFields)
+		if got != tc.expected {
+			t.Errorf("Expected:\n%q\ngot:\n%q", tc.expected, got)
+		}
+	}
+}
+
+func TestCreate(t *testing.T) {
+	db, log := fakedb.Open(func(query string, args []sqldriver.Value) fakedb.Result {
+		rows := make([][]sqldriver.Value, 0, len(args))
+		for i, a := range args {
+			rows = append(rows, []sqldriver.Value{a, int64(i + 1)})
+		}
+		return fakedb.Result{Columns: []string{"name", "id"}, Rows: rows}
+	})
+	defer db.Close()
+
+	res, err := New(db).Create(driver.Payload{
+		Entity:       "users",
+		PrimaryKey:   "id",
+		InsertFields: []string{"name"},
+		ReturnFields: []string{"name", "id"},
+		Data: []map[string]interface{}{
+			{"name": "Jon"},
+			{"name": "Arya"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []map[string]interface{}{
+		{"name": "Jon", "id": int64(1)},
+		{"name": "Arya", "id": int64(2)},
+	}
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("Expected:\n%#v\ngot:\n%#v", expected, res)
+	}
+
+	stmts := log.Statements()
+	if len(stmts) != 3 || stmts[0].Query != "BEGIN" || stmts[2].Query != "COMMIT" {
+		t.Fatalf("Expected single statement in transaction, got %q", log.Queries())
+	}
+	if !strings.Contains(stmts[1].Query, `RETURNING "name", "id"`) {
+		t.Errorf("Expected RETURNING clause, got %q", stmts[1].Query)
+	}
+	if !reflect.DeepEqual(stmts[1].Args, []sqldriver.Value{"Jon", "Arya"}) {
+		t.Errorf("Invalid args: %#v", stmts[1].Args)
+	}
+}
+
+func TestCreateRollback(t *testing.T) {
+	db, log := fakedb.Open(func(query string, args []sqldriver.Value) fakedb.Result {
+		return fakedb.Result{Err: errors.New("duplicate key")}
+	})
+	defer db.Close()
+
+	_, err := New(db).Create(driver.Payload{
+		Entity:       "users",
+		InsertFields: []string{"name"},
+		ReturnFields: []string{"name"},
+		Data:         []map[string]interface{}{{"name": "Jon"}},
+	})
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+	if q := log.Queries(); q[len(q)-1] != "ROLLBACK" {
+		t.Errorf("Expected rollback, got %q", q)
+	}
+}
+
+func TestCreateDefaultValues(t *testing.T) {
+	var id int64
+	db, log := fakedb.Open(func(query string, args []sqldriver.Value) fakedb.Result {
+		id++
+		return fakedb.Result{Columns: []string{"id"}, Rows: [][]sqldriver.Value{{id}}}
+	})
+	defer db.Close()
+
+	res, err := New(db).Create(driver.Payload{
+		Entity:       "users",
+		PrimaryKey:   "id",
+		ReturnFields: []string{"id"},
+		Data:         []map[string]interface{}{{}, {}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []map[string]interface{}{{"id": int64(1)}, {"id": int64(2)}}
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("Expected:\n%#v\ngot:\n%#v", expected, res)
+	}
+	q := "\nINSERT INTO \"users\" DEFAULT VALUES RETURNING \"id\""
+	if qs := log.Queries(); !reflect.DeepEqual([]string{"BEGIN", q, q, "COMMIT"}, qs) {
+		t.Errorf("Unexpected queries: %q", qs)
+	}
+}
+
+func TestCreateChunks(t *testing.T) {
+	db, log := fakedb.Open(func(query string, args []sqldriver.Value) fakedb.Result {
+		rows := make([][]sqldriver.Value, 0, len(args)/2)
+		for i := 0; i < len(args); i += 2 {
+			rows = append(rows, []sqldriver.Value{args[i]})
+		}
+		return fakedb.Result{Columns: []string{"name"}, Rows: rows}
+	})
+	defer db.Close()
+
+	data := make([]map[string]interface{}, 5)
+	for i := range data {
+		data[i] = map[string]interface{}{"name": string(rune('a' + i)), "email": "x"}
+	}
+	pg := newPostgres(db, 4)
+	res, err := pg.Create(driver.Payload{
+		Entity:       "users",
+		InsertFields: []string{"name", "email"},
+		ReturnFields: []string{"name"},
+		Data:         data,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != len(data) {
+		t.Fatalf("Expected %d records, got %d", len(data), len(res))
+	}
+	for i, r := range res {
+		if r["name"] != data[i]["name"] {
+			t.Errorf("Record %d: expected %v, got %v", i, data[i]["name"], r["name"])
+		}
+	}
+	stmts := log.Statements()
+	// BEGIN, 3 inserts of 2, 2 and 1 records, COMMIT
+	if len(stmts) != 5 {
+		t.Fatalf("Expected 3 inserts, got %q", log.Queries())
+	}
+	for i, n := range []int{4, 4, 2} {
+		if len(stmts[i+1].Args) != n {
+			t.Errorf("Insert %d: expected %d args, got %d", i, n, len(stmts[i+1].Args))
+		}
+	}
+}
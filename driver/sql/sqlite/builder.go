@@ -0,0 +1,59 @@
+package sqlite
+
+import (
+	"github.com/josephbuchma/seedr/driver/sql"
+)
+
+// dialect is a SQLite sql.Dialect.
+// RETURNING is supported since SQLite 3.35.0 (see supportsReturning).
+type dialect struct {
+	returning bool
+}
+
+func (dialect) QuoteIdent(name string) string {
+	return sql.QuoteIdent(name, `"`)
+}
+
+func (dialect) Placeholder(int) string {
+	return "?"
+}
+
+func (d dialect) Returning() bool {
+	return d.returning
+}
+
+func (d dialect) IgnoreConflict(key []string) string {
+	return sql.OnConflict(d, key)
+}
+
+// LastInsertID is LastInsertIDReturning if RETURNING is supported,
+// otherwise records are inserted one by one and fetched by rowid.
+func (d dialect) LastInsertID() sql.LastInsertID {
+	if d.returning {
+		return sql.LastInsertIDReturning
+	}
+	return sql.LastInsertIDLast
+}
+
+func bsql() *sql.SQLBuilder {
+	return sql.NewBuilder(dialect{})
+}
+
+func insertBatchSQL(n int, table string, insertFields []string) string {
+	return bsql().Insert(table, insertFields, n).String()
+}
+
+// insertReturningSQL has RETURNING clause only if dl supports it.
+func insertReturningSQL(dl dialect, n int, table string, insertFields, returnFields []string) string {
+	return sql.NewBuilder(dl).Insert(table, insertFields, n).Returning(returnFields).String()
+}
+
+// insertDefaultSQL inserts single record with default values of all columns.
+func insertDefaultSQL(dl dialect, table string, returnFields []string) string {
+	return sql.NewBuilder(dl).InsertDefault(table).Returning(returnFields).String()
+}
+
+// selectByRowIDSQL selects record by rowid (e.g. returned by sql.Result.LastInsertId).
+func selectByRowIDSQL(table string, selectFields []string) string {
+	return bsql().Select(selectFields).From(table).WhereEq([]string{"rowid"}).String()
+}
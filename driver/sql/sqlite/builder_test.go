@@ -0,0 +1,35 @@
+package sqlite
+
+import "testing"
+
+func TestDialect(t *testing.T) {
+	testCases := []struct {
+		got, expected string
+	}{
+		{
+			insertReturningSQL(dialect{returning: true}, 2, "order", []string{"key"}, []string{"key", "id"}),
+			"\nINSERT INTO \"order\" (\"key\") VALUES \n(?),\n(?) RETURNING \"key\", \"id\"",
+		},
+		{
+			insertReturningSQL(dialect{}, 1, "order", []string{"key"}, []string{"id"}),
+			"\nINSERT INTO \"order\" (\"key\") VALUES (?)",
+		},
+		{
+			insertDefaultSQL(dialect{returning: true}, "visits", []string{"id"}),
+			"\nINSERT INTO \"visits\" DEFAULT VALUES RETURNING \"id\"",
+		},
+		{
+			selectByRowIDSQL("order", []string{"key"}),
+			"\nSELECT \"key\" FROM \"order\" WHERE \"rowid\"=?",
+		},
+		{
+			bsql().Insert("users", []string{"email"}, 1).IgnoreConflict([]string{"email"}).String(),
+			"\nINSERT INTO \"users\" (\"email\") VALUES (?) ON CONFLICT (\"email\") DO NOTHING",
+		},
+	}
+	for _, tc := range testCases {
+		if tc.got != tc.expected {
+			t.Errorf("Expected:\n%q\ngot:\n%q", tc.expected, tc.got)
+		}
+	}
+}
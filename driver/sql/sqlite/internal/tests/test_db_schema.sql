@@ -0,0 +1,95 @@
+drop table if exists users;
+create table users (
+    id         integer primary key autoincrement,
+    email      varchar(250) not null,
+    name       varchar(250) not null,
+    active     boolean default false,
+    checkin    datetime,
+    created_at datetime not null default current_timestamp
+);
+create unique index users_email on users (email);
+
+drop table if exists articles;
+create table articles (
+    id           integer primary key autoincrement,
+    author_id    integer not null,
+    title        varchar(250) not null,
+    body         text not null,
+    created_at   datetime not null default current_timestamp
+);
+
+drop table if exists clubs;
+create table clubs (
+    id          integer primary key autoincrement,
+    name        varchar(250) not null
+);
+
+drop table if exists clubs_to_users;
+create table clubs_to_users (
+    id          integer primary key autoincrement,
+    club_id     integer not null,
+    user_id     integer not null
+);
+
+drop table if exists tags;
+create table tags (
+    id          char(26) primary key,
+    name        varchar(250) not null
+);
+
+drop table if exists articles_to_tags;
+create table articles_to_tags (
+    id          blob primary key,
+    article_id  integer not null,
+    tag_id      char(26) not null
+);
+
+drop table if exists labels;
+create table labels (
+    uuid        char(36) primary key,
+    name        varchar(250) not null
+);
+
+drop table if exists tags_to_labels;
+create table tags_to_labels (
+    id          integer primary key autoincrement,
+    tag_id      char(26) not null,
+    label_uuid  char(36) not null
+);
+
+drop table if exists hellota_fields;
+create table hellota_fields (
+    id integer primary key autoincrement,
+    a integer,
+    b text,
+    c varchar(200),
+    d date,
+    e boolean,
+    f integer,
+    g text,
+    h varchar(200),
+    i date,
+    j boolean,
+    k integer,
+    l text,
+    m varchar(200),
+    n date,
+    o boolean,
+    p integer,
+    q text,
+    r varchar(200),
+    s date,
+    t boolean,
+    u integer,
+    v text,
+    w varchar(200),
+    x date,
+    y boolean,
+    z integer
+);
+
+drop table if exists visits;
+create table visits (
+    id         integer primary key autoincrement,
+    created_at datetime not null default current_timestamp
+);
@@ -0,0 +1,231 @@
+// Package sqlite is a SQLite driver for Seedr.
+// Entity (which represents table name in this case) must be specified for each Factory.
+// Records are inserted one by one, and fetched back using INSERT ... RETURNING
+// if SQLite supports it (3.35.0+), or by rowid otherwise.
+// Order of rows returned by multi-row INSERT ... RETURNING is unspecified,
+// so records are batched only when nothing has to be returned.
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/josephbuchma/seedr/driver"
+	seedrsql "github.com/josephbuchma/seedr/driver/sql"
+)
+
+// maxVariables is a default SQLITE_MAX_VARIABLE_NUMBER
+// of SQLite versions prior to 3.32.0
+const maxVariables = 999
+
+// SQLite driver for Seedr
+type SQLite struct {
+	*seedrsql.Driver
+	features features
+}
+
+type features struct {
+	once      sync.Once
+	returning bool
+}
+
+// detect uses db that is passed to insert, because it may be a transaction
+// which holds the only connection of in-memory database.
+func (f *features) detect(ctx context.Context, db seedrsql.DB) {
+	f.once.Do(func() {
+		f.returning = supportsReturning(ctx, db)
+	})
+}
+
+// New creates new Driver (see sql.NewDriver).
+func New(db seedrsql.DB) driver.Driver {
+	s := &SQLite{}
+	s.Driver = seedrsql.NewDriver(db, dialect{}, maxVariables, s.insert)
+	return s
+}
+
+func (s *SQLite) insert(ctx context.Context, db seedrsql.DB, p driver.Payload) ([]map[string]interface{}, error) {
+	s.features.detect(ctx, db)
+	dl := dialect{returning: s.features.returning}
+	d, ins := drv{ctx, db, dl}, insertPayload{p.Entity, p.InsertFields, p.ReturnFields, p.Data}
+	switch dl.LastInsertID() {
+	case seedrsql.LastInsertIDReturning:
+		return d.insertReturning(ins)
+	case seedrsql.LastInsertIDLast:
+		return d.insert(ins)
+	default:
+		return nil, seedrsql.UnsupportedLastInsertID(dl)
+	}
+}
+
+// supportsReturning checks if SQLite version is 3.35.0 or newer
+func supportsReturning(ctx context.Context, db seedrsql.DB) bool {
+	var version string
+	if err := db.QueryRowContext(ctx, "SELECT sqlite_version()").Scan(&version); err != nil {
+		return false
+	}
+	var major, minor int
+	if _, err := fmt.Sscanf(version, "%d.%d", &major, &minor); err != nil {
+		return false
+	}
+	return major > 3 || (major == 3 && minor >= 35)
+}
+
+type drv struct {
+	ctx     context.Context
+	db      seedrsql.DB
+	dialect dialect
+}
+
+type insertPayload struct {
+	table                      string
+	insertFields, returnFields []string
+	data                       []map[string]interface{}
+}
+
+func (ip insertPayload) values(data []map[string]interface{}) []interface{} {
+	sls := make([]interface{}, len(ip.insertFields)*len(data))
+	i := 0
+	for _, d := range data {
+		for _, f := range ip.insertFields {
+			sls[i] = d[f]
+			i++
+		}
+	}
+	return sls
+}
+
+func (ip insertPayload) validate() error {
+	if len(ip.data) == 0 {
+		return errors.New("Nothing to create")
+	}
+	return nil
+}
+
+func makePtrs(v []interface{}) []interface{} {
+	ptrs := make([]interface{}, len(v))
+	for i := range v {
+		ptrs[i] = &v[i]
+	}
+	return ptrs
+}
+
+func (d drv) query(sql string, vals []interface{}, fields []string) ([]map[string]interface{}, error) {
+	rows, err := d.db.QueryContext(d.ctx, sql, vals...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ret []map[string]interface{}
+	for rows.Next() {
+		vals := make([]interface{}, len(fields))
+		if err := rows.Scan(makePtrs(vals)...); err != nil {
+			return nil, err
+		}
+		r := make(map[string]interface{}, len(vals))
+		for i, f := range fields {
+			r[f] = vals[i]
+		}
+		ret = append(ret, r)
+	}
+	return ret, rows.Err()
+}
+
+// insertReturning inserts records one by one using INSERT ... RETURNING.
+// If there are no ReturnFields, records are inserted in chunks
+// that fit into maxVariables (see insertBatch).
+func (d drv) insertReturning(ins insertPayload) ([]map[string]interface{}, error) {
+	if err := ins.validate(); err != nil {
+		return nil, err
+	}
+	if len(ins.returnFields) == 0 {
+		return nil, d.insertBatch(ins)
+	}
+	ret := make([]map[string]interface{}, 0, len(ins.data))
+	s := d.insertOneSQL(ins)
+	for _, rec := range ins.data {
+		recs, err := d.query(s, ins.values([]map[string]interface{}{rec}), ins.returnFields)
+		if err != nil {
+			return nil, err
+		}
+		if len(recs) != 1 {
+			return nil, fmt.Errorf("failed to fetch inserted record from %s", ins.table)
+		}
+		ret = append(ret, recs[0])
+	}
+	return ret, nil
+}
+
+// insertOneSQL returns INSERT statement of single record with RETURNING clause
+// (if dialect supports it). If there are no fields to insert (e.g. all of them
+// are Auto()), record is inserted with default values.
+func (d drv) insertOneSQL(ins insertPayload) string {
+	if len(ins.insertFields) == 0 {
+		return insertDefaultSQL(d.dialect, ins.table, ins.returnFields)
+	}
+	return insertReturningSQL(d.dialect, 1, ins.table, ins.insertFields, ins.returnFields)
+}
+
+// insertBatch inserts records in chunks that fit into maxVariables,
+// or one by one if there are no fields to insert.
+func (d drv) insertBatch(ins insertPayload) error {
+	if len(ins.insertFields) == 0 {
+		s := insertDefaultSQL(d.dialect, ins.table, nil)
+		for range ins.data {
+			if _, err := d.db.ExecContext(d.ctx, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	chunk := maxVariables / len(ins.insertFields)
+	if chunk == 0 {
+		chunk = 1
+	}
+	for b := 0; b < len(ins.data); b += chunk {
+		e := b + chunk
+		if e > len(ins.data) {
+			e = len(ins.data)
+		}
+		if _, err := d.db.ExecContext(d.ctx, insertBatchSQL(e-b, ins.table, ins.insertFields), ins.values(ins.data[b:e])...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insert inserts records one by one and fetches each of them by rowid
+// returned by sql.Result.LastInsertId.
+func (d drv) insert(ins insertPayload) ([]map[string]interface{}, error) {
+	if err := ins.validate(); err != nil {
+		return nil, err
+	}
+	if len(ins.returnFields) == 0 {
+		return nil, d.insertBatch(ins)
+	}
+	ret := make([]map[string]interface{}, 0, len(ins.data))
+	s := d.insertOneSQL(ins)
+	sl := selectByRowIDSQL(ins.table, ins.returnFields)
+	for _, rec := range ins.data {
+		res, err := d.db.ExecContext(d.ctx, s, ins.values([]map[string]interface{}{rec})...)
+		if err != nil {
+			return nil, err
+		}
+		rowID, err := res.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+		recs, err := d.query(sl, []interface{}{rowID}, ins.returnFields)
+		if err != nil {
+			return nil, err
+		}
+		if len(recs) != 1 {
+			return nil, fmt.Errorf("failed to fetch inserted record from %s", ins.table)
+		}
+		ret = append(ret, recs[0])
+	}
+	return ret, nil
+}
@@ -0,0 +1,160 @@
+package sqlite
+
+import (
+	sqldriver "database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/josephbuchma/seedr/driver"
+	"github.com/josephbuchma/seedr/driver/sql/internal/fakedb"
+)
+
+func testPayload(n int) driver.Payload {
+	p := driver.Payload{
+		Entity:       "users",
+		PrimaryKey:   "id",
+		InsertFields: []string{"name"},
+		ReturnFields: []string{"name", "id"},
+	}
+	for i := 0; i < n; i++ {
+		p.Data = append(p.Data, map[string]interface{}{"name": "Jon"})
+	}
+	return p
+}
+
+func TestCreateReturning(t *testing.T) {
+	id := int64(0)
+	db, log := fakedb.Open(func(query string, args []sqldriver.Value) fakedb.Result {
+		if strings.Contains(query, "sqlite_version") {
+			return fakedb.Result{Columns: []string{"v"}, Rows: [][]sqldriver.Value{{"3.45.1"}}}
+		}
+		var rows [][]sqldriver.Value
+		for _, a := range args {
+			id++
+			rows = append(rows, []sqldriver.Value{a, id})
+		}
+		return fakedb.Result{Columns: []string{"name", "id"}, Rows: rows}
+	})
+	defer db.Close()
+
+	p := testPayload(3)
+	for i, name := range []string{"Jon", "Arya", "Sansa"} {
+		p.Data[i]["name"] = name
+	}
+	res, err := New(db).Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []map[string]interface{}{
+		{"name": "Jon", "id": int64(1)},
+		{"name": "Arya", "id": int64(2)},
+		{"name": "Sansa", "id": int64(3)},
+	}
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("Expected:\n%#v\ngot:\n%#v", expected, res)
+	}
+	// order of rows returned by multi-row insert is unspecified,
+	// so every record must be inserted by separate statement
+	for _, st := range log.Statements() {
+		if strings.Contains(st.Query, "RETURNING") && len(st.Args) != 1 {
+			t.Errorf("Expected single record per INSERT ... RETURNING, got %q", st.Query)
+		}
+	}
+}
+
+func TestCreateChunks(t *testing.T) {
+	db, log := fakedb.Open(func(query string, args []sqldriver.Value) fakedb.Result {
+		if strings.Contains(query, "sqlite_version") {
+			return fakedb.Result{Columns: []string{"v"}, Rows: [][]sqldriver.Value{{"3.45.1"}}}
+		}
+		return fakedb.Result{RowsAffected: int64(len(args))}
+	})
+	defer db.Close()
+
+	p := testPayload(maxVariables + 1)
+	p.ReturnFields = nil
+	res, err := New(db).Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != nil {
+		t.Errorf("Expected no results, got %d", len(res))
+	}
+	inserts := 0
+	for _, q := range log.Queries() {
+		if strings.Contains(q, "INSERT") {
+			inserts++
+		}
+	}
+	if inserts != 2 {
+		t.Errorf("Expected 2 chunks, got %d", inserts)
+	}
+}
+
+func TestCreateLastInsertRowID(t *testing.T) {
+	id := int64(0)
+	db, log := fakedb.Open(func(query string, args []sqldriver.Value) fakedb.Result {
+		switch {
+		case strings.Contains(query, "sqlite_version"):
+			return fakedb.Result{Columns: []string{"v"}, Rows: [][]sqldriver.Value{{"3.22.0"}}}
+		case strings.Contains(query, "INSERT"):
+			id++
+			return fakedb.Result{LastInsertID: id, RowsAffected: 1}
+		}
+		return fakedb.Result{Columns: []string{"name", "id"}, Rows: [][]sqldriver.Value{{"Jon", id}}}
+	})
+	defer db.Close()
+
+	res, err := New(db).Create(testPayload(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []map[string]interface{}{
+		{"name": "Jon", "id": int64(1)},
+		{"name": "Jon", "id": int64(2)},
+	}
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("Expected:\n%#v\ngot:\n%#v", expected, res)
+	}
+	selects := 0
+	for _, q := range log.Queries() {
+		if strings.Contains(q, `WHERE "rowid"=?`) {
+			selects++
+		}
+	}
+	if selects != 2 {
+		t.Errorf("Expected record to be fetched after each insert, got %q", log.Queries())
+	}
+}
+
+func TestCreateDefaultValues(t *testing.T) {
+	id := int64(0)
+	db, log := fakedb.Open(func(query string, args []sqldriver.Value) fakedb.Result {
+		switch {
+		case strings.Contains(query, "sqlite_version"):
+			return fakedb.Result{Columns: []string{"v"}, Rows: [][]sqldriver.Value{{"3.22.0"}}}
+		case strings.Contains(query, "INSERT"):
+			id++
+			return fakedb.Result{LastInsertID: id, RowsAffected: 1}
+		}
+		return fakedb.Result{Columns: []string{"id"}, Rows: [][]sqldriver.Value{{id}}}
+	})
+	defer db.Close()
+
+	p := testPayload(2)
+	p.InsertFields, p.ReturnFields = nil, []string{"id"}
+	res, err := New(db).Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []map[string]interface{}{{"id": int64(1)}, {"id": int64(2)}}
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("Expected:\n%#v\ngot:\n%#v", expected, res)
+	}
+	for _, q := range log.Queries() {
+		if strings.Contains(q, "INSERT") && !strings.Contains(q, `INSERT INTO "users" DEFAULT VALUES`) {
+			t.Errorf("Expected INSERT with default values, got %q", q)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+)
+
+// DB is a database handle used by SQL drivers.
+// It is implemented by *sql.DB and *sql.Tx.
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// CanBegin reports if db is able to start new transaction (e.g. it's *sql.DB).
+func CanBegin(db DB) bool {
+	_, ok := db.(txBeginner)
+	return ok
+}
+
+// Tx is a transaction or savepoint started by Begin.
+type Tx struct {
+	DB
+	commit, rollback func() error
+}
+
+// Commit commits transaction or releases savepoint.
+func (tx *Tx) Commit() error {
+	return tx.commit()
+}
+
+// Rollback aborts transaction or rolls back to savepoint.
+func (tx *Tx) Rollback() error {
+	return tx.rollback()
+}
+
+var savepointSeq int64
+
+// Begin starts transaction if db can begin one (see CanBegin).
+// Otherwise (e.g. db is *sql.Tx) nested transaction
+// is emulated using SAVEPOINT.
+func Begin(ctx context.Context, db DB) (*Tx, error) {
+	if b, ok := db.(txBeginner); ok {
+		tx, err := b.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &Tx{DB: tx, commit: tx.Commit, rollback: tx.Rollback}, nil
+	}
+	name := fmt.Sprintf("seedr_sp_%d", atomic.AddInt64(&savepointSeq, 1))
+	if _, err := db.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return nil, err
+	}
+	exec := func(query string) func() error {
+		return func() error {
+			_, err := db.ExecContext(context.Background(), query)
+			return err
+		}
+	}
+	return &Tx{
+		DB:       db,
+		commit:   exec("RELEASE SAVEPOINT " + name),
+		rollback: exec("ROLLBACK TO SAVEPOINT " + name),
+	}, nil
+}
@@ -0,0 +1,58 @@
+package sql
+
+import (
+	"context"
+	sqldriver "database/sql/driver"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/josephbuchma/seedr/driver/sql/internal/fakedb"
+)
+
+func TestBegin(t *testing.T) {
+	db, log := fakedb.Open(func(string, []sqldriver.Value) fakedb.Result {
+		return fakedb.Result{}
+	})
+	defer db.Close()
+
+	ctx := context.Background()
+	tx, err := Begin(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if CanBegin(tx) {
+		t.Error("Expected transaction to be unable to begin new transaction")
+	}
+	sp, err := Begin(ctx, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sp.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	sp, err = Begin(ctx, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sp.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"BEGIN",
+		"SAVEPOINT seedr_sp_N", "ROLLBACK TO SAVEPOINT seedr_sp_N",
+		"SAVEPOINT seedr_sp_N", "RELEASE SAVEPOINT seedr_sp_N",
+		"COMMIT",
+	}
+	var got []string
+	for _, q := range log.Queries() {
+		got = append(got, regexp.MustCompile(`\d+$`).ReplaceAllString(q, "N"))
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("Expected:\n%q\ngot:\n%q", expected, got)
+	}
+}
@@ -0,0 +1,39 @@
+package sql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/josephbuchma/seedr/driver"
+)
+
+// Update updates records of p (see driver.Updater) one by one using SQL of Dialect d.
+// If db can begin transaction, all records are updated in single transaction.
+func Update(ctx context.Context, db DB, p driver.Payload, d Dialect) error {
+	if p.PrimaryKey == "" || len(p.InsertFields) == 0 {
+		return errors.New("PrimaryKey and InsertFields are required to update records")
+	}
+	if CanBegin(db) {
+		tx, err := Begin(ctx, db)
+		if err != nil {
+			return err
+		}
+		if err := Update(ctx, tx, p, d); err != nil {
+			tx.Rollback()
+			return err
+		}
+		return tx.Commit()
+	}
+	s := NewBuilder(d).Update(p.Entity, p.InsertFields).WhereEq([]string{p.PrimaryKey}).String()
+	for _, rec := range p.Data {
+		vals := make([]interface{}, 0, len(p.InsertFields)+1)
+		for _, f := range p.InsertFields {
+			vals = append(vals, rec[f])
+		}
+		vals = append(vals, rec[p.PrimaryKey])
+		if _, err := db.ExecContext(ctx, s, vals...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
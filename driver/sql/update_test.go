@@ -0,0 +1,41 @@
+package sql
+
+import (
+	"context"
+	sqldriver "database/sql/driver"
+	"reflect"
+	"testing"
+
+	"github.com/josephbuchma/seedr/driver"
+	"github.com/josephbuchma/seedr/driver/sql/internal/fakedb"
+)
+
+func TestUpdate(t *testing.T) {
+	db, log := fakedb.Open(func(string, []sqldriver.Value) fakedb.Result {
+		return fakedb.Result{RowsAffected: 1}
+	})
+	defer db.Close()
+
+	p := driver.Payload{
+		Entity:       "users",
+		PrimaryKey:   "id",
+		InsertFields: []string{"name", "active"},
+		Data: []map[string]interface{}{
+			{"id": int64(1), "name": "a", "active": false},
+			{"id": int64(2), "name": "b", "active": true},
+		},
+	}
+	if err := Update(context.Background(), db, p, qmarks{}); err != nil {
+		t.Fatal(err)
+	}
+	q := "\nUPDATE users SET name=?, active=? WHERE id=?"
+	expected := []fakedb.Statement{
+		{Query: "BEGIN"},
+		{Query: q, Args: []sqldriver.Value{"a", false, int64(1)}},
+		{Query: q, Args: []sqldriver.Value{"b", true, int64(2)}},
+		{Query: "COMMIT"},
+	}
+	if got := log.Statements(); !reflect.DeepEqual(expected, got) {
+		t.Errorf("Expected:\n%q\ngot:\n%q", expected, got)
+	}
+}
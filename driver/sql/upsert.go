@@ -0,0 +1,172 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/josephbuchma/seedr/driver"
+)
+
+// Upsert upserts records of p (see driver.Upserter) one by one using SQL of Dialect d.
+// Every record is inserted with conflict clause of Dialect d (see Dialect.IgnoreConflict),
+// so record that exists (or is inserted concurrently) is left unchanged, and it's found
+// if no rows are affected. Database doesn't detect conflicts of NULL values, so records
+// with NULL values of p.Conflict.Key are selected (by IS NULL, see SQLBuilder.WhereMatch)
+// and inserted only if they are not found.
+// Conflict.Update fields of found records are updated if Strategy is ConflictUpdate.
+// Then every record is selected by p.Conflict.Key, so results always contain stored
+// values of ReturnFields (e.g. real primary key).
+// If db can begin transaction, all records are upserted in single transaction.
+func Upsert(ctx context.Context, db DB, p driver.Payload, d Dialect) (results []map[string]interface{}, found []bool, err error) {
+	if p.Conflict == nil || len(p.Conflict.Key) == 0 {
+		return nil, nil, errors.New("Conflict with Key is required to upsert records")
+	}
+	if len(p.Data) == 0 || len(p.InsertFields) == 0 {
+		return nil, nil, errors.New("Nothing to create")
+	}
+	given := make(map[string]bool, len(p.InsertFields))
+	for _, f := range p.InsertFields {
+		given[f] = true
+	}
+	for _, k := range p.Conflict.Key {
+		if !given[k] {
+			return nil, nil, fmt.Errorf("Conflict key %q of %s is not in InsertFields", k, p.Entity)
+		}
+	}
+	if CanBegin(db) {
+		tx, err := Begin(ctx, db)
+		if err != nil {
+			return nil, nil, err
+		}
+		ret, found, err := Upsert(ctx, tx, p, d)
+		if err != nil {
+			tx.Rollback()
+			return nil, nil, err
+		}
+		return ret, found, tx.Commit()
+	}
+
+	key := p.Conflict.Key
+	var update []string
+	if p.Conflict.Strategy == driver.ConflictUpdate {
+		update = p.Conflict.Update
+	}
+	// key fields are selected to find out whether record exists
+	fields := p.ReturnFields
+	if len(fields) == 0 {
+		fields = key
+	}
+	ins := NewBuilder(d).Insert(p.Entity, p.InsertFields, 1).IgnoreConflict(key).String()
+	if len(p.ReturnFields) > 0 {
+		results = make([]map[string]interface{}, len(p.Data))
+	}
+	found = make([]bool, len(p.Data))
+	for i, rec := range p.Data {
+		null, keys := matchValues(key, rec)
+		sel := NewBuilder(d).Select(fields).From(p.Entity).WhereMatch(key, null).String()
+		vals := values(p.InsertFields, rec)
+		// stored is a selected record that is still unchanged
+		var stored map[string]interface{}
+		if hasNull(null) {
+			// NULL values don't conflict in unique index, so record is looked up by IS NULL
+			stored, err = queryRecord(ctx, db, sel, keys, fields)
+			if found[i] = err == nil; err == errNotFound {
+				_, err = db.ExecContext(ctx, ins, vals...)
+			}
+		} else {
+			var n int64
+			n, err = execAffected(ctx, db, ins, vals)
+			found[i] = n == 0
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if found[i] && len(update) > 0 {
+			s := NewBuilder(d).Update(p.Entity, update).WhereMatch(key, null).String()
+			if _, err = db.ExecContext(ctx, s, append(values(update, rec), keys...)...); err != nil {
+				return nil, nil, err
+			}
+			stored = nil
+		}
+		if results == nil {
+			continue
+		}
+		if stored == nil {
+			if stored, err = queryRecord(ctx, db, sel, keys, fields); err != nil {
+				return nil, nil, fmt.Errorf("record of %s with %v=%v: %w", p.Entity, key, keys, err)
+			}
+		}
+		if p.Conflict.Strategy == driver.ConflictIgnore {
+			for _, f := range p.ReturnFields {
+				if given[f] {
+					stored[f] = rec[f]
+				}
+			}
+		}
+		results[i] = stored
+	}
+	return results, found, nil
+}
+
+// hasNull reports whether any of values is NULL (see matchValues).
+func hasNull(null []bool) bool {
+	for _, n := range null {
+		if n {
+			return true
+		}
+	}
+	return false
+}
+
+// execAffected executes query and returns number of affected rows.
+func execAffected(ctx context.Context, db DB, query string, args []interface{}) (int64, error) {
+	res, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// values returns values of given fields of rec.
+func values(fields []string, rec map[string]interface{}) []interface{} {
+	vals := make([]interface{}, len(fields))
+	for i, f := range fields {
+		vals[i] = rec[f]
+	}
+	return vals
+}
+
+// errNotFound is returned by queryRecord if query returns no records.
+var errNotFound = errors.New("not found")
+
+// queryRecord returns fields of single record selected by query.
+func queryRecord(ctx context.Context, db DB, query string, args []interface{}, fields []string) (map[string]interface{}, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errNotFound
+	}
+	vals := make([]interface{}, len(fields))
+	ptrs := make([]interface{}, len(fields))
+	for i := range vals {
+		ptrs[i] = &vals[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return nil, err
+	}
+	if rows.Next() {
+		return nil, errors.New("key is not unique")
+	}
+	rec := make(map[string]interface{}, len(fields))
+	for i, f := range fields {
+		rec[f] = vals[i]
+	}
+	return rec, rows.Err()
+}
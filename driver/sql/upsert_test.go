@@ -0,0 +1,152 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	sqldriver "database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/josephbuchma/seedr/driver"
+	"github.com/josephbuchma/seedr/driver/sql/internal/fakedb"
+)
+
+// openUsers returns database with users (id, email, name) table,
+// where email is unique and nullable. It contains jon@example.com with id 7.
+// INSERT of existing email affects no rows (see Dialect.IgnoreConflict).
+func openUsers() (*sql.DB, *fakedb.Log) {
+	rows := map[interface{}][]sqldriver.Value{
+		"jon@example.com": {int64(7), "jon@example.com", "Stored"},
+	}
+	return fakedb.Open(func(q string, args []sqldriver.Value) fakedb.Result {
+		var key interface{}
+		if !strings.Contains(q, "IS NULL") && len(args) > 0 {
+			key = args[len(args)-1]
+		}
+		switch {
+		case strings.HasPrefix(q, "\nSELECT"):
+			res := fakedb.Result{Columns: []string{"id", "email", "name"}}
+			if r, ok := rows[key]; ok {
+				res.Rows = [][]sqldriver.Value{r}
+			}
+			return res
+		case strings.HasPrefix(q, "\nINSERT"):
+			if _, ok := rows[args[0]]; ok && args[0] != nil {
+				return fakedb.Result{}
+			}
+			rows[args[0]] = []sqldriver.Value{int64(len(rows) + 7), args[0], args[1]}
+		case strings.HasPrefix(q, "\nUPDATE"):
+			rows[key][2] = args[0]
+		}
+		return fakedb.Result{RowsAffected: 1}
+	})
+}
+
+func TestUpsert(t *testing.T) {
+	p := driver.Payload{
+		Entity:       "users",
+		PrimaryKey:   "id",
+		InsertFields: []string{"email", "name"},
+		ReturnFields: []string{"id", "email", "name"},
+		Data:         []map[string]interface{}{{"email": "jon@example.com", "name": "Jon"}},
+	}
+	ins := "\nINSERT INTO users (email, name) VALUES (?,?) ON CONFLICT (email) DO NOTHING"
+	sel := "\nSELECT id, email, name FROM users WHERE email=?"
+	testCases := []struct {
+		conflict driver.Conflict
+		queries  []string
+		expected map[string]interface{}
+	}{
+		{
+			driver.Conflict{Strategy: driver.ConflictIgnore, Key: []string{"email"}},
+			[]string{ins, sel},
+			map[string]interface{}{"id": int64(7), "email": "jon@example.com", "name": "Jon"},
+		},
+		{
+			driver.Conflict{Strategy: driver.ConflictFetch, Key: []string{"email"}, Update: []string{"name"}},
+			[]string{ins, sel},
+			map[string]interface{}{"id": int64(7), "email": "jon@example.com", "name": "Stored"},
+		},
+		{
+			driver.Conflict{Strategy: driver.ConflictUpdate, Key: []string{"email"}, Update: []string{"name"}},
+			[]string{ins, "\nUPDATE users SET name=? WHERE email=?", sel},
+			map[string]interface{}{"id": int64(7), "email": "jon@example.com", "name": "Jon"},
+		},
+	}
+	for _, tc := range testCases {
+		db, log := openUsers()
+		c := tc.conflict
+		p.Conflict = &c
+		res, found, err := Upsert(context.Background(), db, p, qmarks{})
+		db.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual([]map[string]interface{}{tc.expected}, res) {
+			t.Errorf("Expected:\n%#v\ngot:\n%#v", tc.expected, res)
+		}
+		if !reflect.DeepEqual([]bool{true}, found) {
+			t.Errorf("Expected record to be found, got %v", found)
+		}
+		expected := append(append([]string{"BEGIN"}, tc.queries...), "COMMIT")
+		if qs := log.Queries(); !reflect.DeepEqual(expected, qs) {
+			t.Errorf("Expected queries:\n%q\ngot:\n%q", expected, qs)
+		}
+	}
+
+	// new record is found out by affected rows of INSERT
+	db, log := openUsers()
+	defer db.Close()
+	p.Conflict = &driver.Conflict{Strategy: driver.ConflictUpdate, Key: []string{"email"}, Update: []string{"name"}}
+	p.Data = []map[string]interface{}{{"email": "arya@example.com", "name": "Arya"}}
+	res, found, err := Upsert(context.Background(), db, p, qmarks{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []map[string]interface{}{{"id": int64(8), "email": "arya@example.com", "name": "Arya"}}
+	if !reflect.DeepEqual(expected, res) || !reflect.DeepEqual([]bool{false}, found) {
+		t.Errorf("Expected new record %#v, got %#v (found: %v)", expected, res, found)
+	}
+	if qs := log.Queries(); !reflect.DeepEqual([]string{"BEGIN", ins, sel, "COMMIT"}, qs) {
+		t.Errorf("Expected insert and select, got:\n%q", qs)
+	}
+
+	p.Conflict = &driver.Conflict{Strategy: driver.ConflictFetch, Key: []string{"id"}}
+	if _, _, err := Upsert(context.Background(), db, p, qmarks{}); err == nil {
+		t.Error("Expected error for key that is not in InsertFields")
+	}
+}
+
+func TestUpsertNullKey(t *testing.T) {
+	db, log := openUsers()
+	defer db.Close()
+
+	p := driver.Payload{
+		Entity:       "users",
+		PrimaryKey:   "id",
+		InsertFields: []string{"email", "name"},
+		ReturnFields: []string{"id", "email", "name"},
+		Data:         []map[string]interface{}{{"email": nil, "name": "Arya"}},
+		Conflict:     &driver.Conflict{Strategy: driver.ConflictFetch, Key: []string{"email"}},
+	}
+	expected := []map[string]interface{}{{"id": int64(8), "email": nil, "name": "Arya"}}
+	for i, exists := range []bool{false, true} {
+		res, found, err := Upsert(context.Background(), db, p, qmarks{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(expected, res) || !reflect.DeepEqual([]bool{exists}, found) {
+			t.Errorf("%d: expected %#v (found: %v), got %#v (found: %v)", i, expected, exists, res, found)
+		}
+	}
+
+	sel := "\nSELECT id, email, name FROM users WHERE email IS NULL"
+	expectedQueries := []string{
+		"BEGIN", sel, "\nINSERT INTO users (email, name) VALUES (?,?) ON CONFLICT (email) DO NOTHING", sel, "COMMIT",
+		"BEGIN", sel, "COMMIT",
+	}
+	if qs := log.Queries(); !reflect.DeepEqual(expectedQueries, qs) {
+		t.Errorf("Expected queries:\n%q\ngot:\n%q", expectedQueries, qs)
+	}
+}
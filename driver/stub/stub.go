@@ -0,0 +1,54 @@
+// Package stub is a driver that doesn't store anything, but assigns
+// fake primary keys, so stubbed records can be related to each other.
+package stub
+
+import (
+	"context"
+	"sync"
+
+	"github.com/josephbuchma/seedr/driver"
+)
+
+// StubDriver assigns per-entity incrementing primary keys
+// to records that don't have them (e.g. Auto() primary keys).
+type StubDriver struct {
+	mu  sync.Mutex
+	ids map[string]int64
+}
+
+// New creates new StubDriver. First key of every entity is 1.
+func New() *StubDriver {
+	return &StubDriver{ids: make(map[string]int64)}
+}
+
+// Create returns copies of payload Data with primary keys assigned.
+func (d *StubDriver) Create(p driver.Payload) ([]map[string]interface{}, error) {
+	assign := false
+	for _, f := range p.ReturnFields {
+		if f == p.PrimaryKey && p.PrimaryKey != "" {
+			assign = true
+		}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	ret := make([]map[string]interface{}, len(p.Data))
+	for i, rec := range p.Data {
+		ret[i] = make(map[string]interface{}, len(rec)+1)
+		for k, v := range rec {
+			ret[i][k] = v
+		}
+		if _, ok := rec[p.PrimaryKey]; assign && !ok {
+			d.ids[p.Entity]++
+			ret[i][p.PrimaryKey] = d.ids[p.Entity]
+		}
+	}
+	return ret, nil
+}
+
+// CreateContext is same as Create unless ctx is done.
+func (d *StubDriver) CreateContext(ctx context.Context, p driver.Payload) ([]map[string]interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return d.Create(p)
+}
@@ -0,0 +1,91 @@
+package seedr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/josephbuchma/seedr/driver"
+)
+
+// ErrTraitNotFound is returned when requested public trait does not exist.
+var ErrTraitNotFound = errors.New("trait not found")
+
+// ErrNotCreated is returned by Reload* and Update* of instances that were not
+// stored by "create" driver (e.g. built by Build* or BuildStubbed*).
+var ErrNotCreated = errors.New("instance is not created")
+
+func traitNotFound(name string) error {
+	return fmt.Errorf("%w: %q", ErrTraitNotFound, name)
+}
+
+// DriverError is returned when Driver fails to create records.
+type DriverError struct {
+	// Entity is an Entity of factory that failed.
+	Entity string
+	// Payload is a payload that was passed to Driver.
+	Payload driver.Payload
+	// Err is an original error returned by Driver.
+	Err error
+}
+
+func (e *DriverError) Error() string {
+	return fmt.Sprintf("Seedr Driver error (%s): %s", e.Entity, e.Err)
+}
+
+// Unwrap returns original Driver error.
+func (e *DriverError) Unwrap() error {
+	return e.Err
+}
+
+// ScanError is returned when Scan fails on particular struct field.
+type ScanError struct {
+	// Field is a name of struct field.
+	Field string
+	// Key is a name of Trait field that was scanned into Field.
+	// It's empty if Field could not be mapped by MapFieldFunc.
+	Key string
+	// Err is an original error.
+	Err error
+}
+
+func (e *ScanError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("Failed to Scan field %s: %s", e.Field, e.Err)
+	}
+	return fmt.Sprintf("Failed to Scan %q into field %s: %s", e.Key, e.Field, e.Err)
+}
+
+// Unwrap returns original error.
+func (e *ScanError) Unwrap() error {
+	return e.Err
+}
+
+// StrictScanError is returned by Scan in strict mode (see SetStrictScan)
+// when struct fields don't match record keys.
+type StrictScanError struct {
+	// Type is a name of scanned struct type.
+	Type string
+	// UnmappedFields are struct fields that have no matching record key,
+	// in "Field (key)" format, where key is a result of MapFieldFunc.
+	UnmappedFields []string
+	// UnusedKeys are record keys that were not scanned into any struct field.
+	UnusedKeys []string
+}
+
+func (e *StrictScanError) Error() string {
+	var msgs []string
+	if len(e.UnmappedFields) > 0 {
+		msgs = append(msgs, "unmapped struct fields: "+strings.Join(e.UnmappedFields, ", "))
+	}
+	if len(e.UnusedKeys) > 0 {
+		msgs = append(msgs, "unused record keys: "+strings.Join(e.UnusedKeys, ", "))
+	}
+	return fmt.Sprintf("Strict Scan of %s failed: %s", e.Type, strings.Join(msgs, "; "))
+}
+
+func panicOnError(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
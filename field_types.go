@@ -1,19 +1,26 @@
 package seedr
 
 import (
+	crand "crypto/rand"
+	sqldriver "database/sql/driver"
+	"encoding/hex"
 	"fmt"
 	"math/rand"
 	"reflect"
+	"strings"
 	"time"
 )
 
 // Generator provides a way to generate dynamic field values
 // for every instance created by seedr.
 type Generator interface {
-	// Next must return any value of supported type:
+	// Next must return any value of supported type (see Trait):
 	//    - any numeric type
+	//    - string, []byte, bool (e.g. ULID() and UUIDString() yield strings)
 	//    - time.Time
 	//    - sql.Scanner
+	//    - driver.Valuer (e.g. BinaryUUID yielded by UUID())
+	//    - values that have ValueEncoder registered (see SetValueEncoder)
 	//    - nil value (interface{}(nil))
 	Next() interface{}
 }
@@ -35,7 +42,8 @@ type Dependent struct {
 // DependsOn allows to initialize field
 // based on other fields of this trait.
 // Seedr will ensure that listed fields are initialized
-// before this one. It will panic on circular dependency.
+// before this one. Circular dependency is reported as error
+// by Try* methods (other methods panic).
 // Must be followed by #Generate.
 // Example:
 //
@@ -164,6 +172,117 @@ func DummyText(limit int) Generator {
 	})
 }
 
+// BinaryUUID is a UUID that is stored as 16 bytes (e.g. in BINARY(16) column).
+// It can be scanned from 16 bytes or from canonical string representation.
+type BinaryUUID [16]byte
+
+// String returns canonical representation of UUID (xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx).
+func (u BinaryUUID) String() string {
+	h := hex.EncodeToString(u[:])
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+// Value implements driver.Valuer (database/sql/driver).
+func (u BinaryUUID) Value() (sqldriver.Value, error) {
+	return u[:], nil
+}
+
+// Scan implements sql.Scanner.
+func (u *BinaryUUID) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*u = BinaryUUID{}
+		return nil
+	case []byte:
+		if len(src) == len(u) {
+			copy(u[:], src)
+			return nil
+		}
+		return u.Scan(string(src))
+	case string:
+		b, err := hex.DecodeString(strings.ReplaceAll(src, "-", ""))
+		if err == nil && len(b) == len(u) {
+			copy(u[:], b)
+			return nil
+		}
+	}
+	return fmt.Errorf("can't scan %T (%v) into BinaryUUID", src, src)
+}
+
+func newUUID() BinaryUUID {
+	var u BinaryUUID
+	_, err := crand.Read(u[:])
+	panicOnError(err)
+	u[6] = u[6]&0x0f | 0x40 // version 4
+	u[8] = u[8]&0x3f | 0x80 // RFC 4122 variant
+	return u
+}
+
+// UUID generates random (version 4) UUIDs as BinaryUUID values,
+// that are stored as 16 bytes (e.g. in BINARY(16) primary key column).
+// Use UUIDString for text columns.
+func UUID() Generator {
+	return Func(func() interface{} {
+		return newUUID()
+	})
+}
+
+// UUIDString generates random (version 4) UUIDs in canonical string representation
+// (e.g. for CHAR(36) column).
+func UUIDString() Generator {
+	return Func(func() interface{} {
+		return newUUID().String()
+	})
+}
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULID generates ULIDs (https://github.com/ulid/spec) as 26 characters strings
+// (e.g. for CHAR(26) column). ULIDs of the same generator are strictly increasing,
+// so records are sorted by primary key in order of creation.
+func ULID() Generator {
+	var last [16]byte
+	return Func(func() interface{} {
+		var id [16]byte
+		ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+		for i := 0; i < 6; i++ {
+			id[i] = byte(ms >> (40 - 8*i))
+		}
+		if string(id[:6]) > string(last[:6]) {
+			_, err := crand.Read(id[6:])
+			panicOnError(err)
+		} else {
+			// same millisecond (or clock went back): increment random part of previous ULID
+			id = last
+			for i := 15; i >= 6; i-- {
+				id[i]++
+				if id[i] != 0 {
+					break
+				}
+			}
+		}
+		last = id
+		return encodeULID(id)
+	})
+}
+
+// encodeULID encodes 128 bits of id in 26 characters of Crockford's base32
+// (first character holds only 3 bits).
+func encodeULID(id [16]byte) string {
+	var s [26]byte
+	for i := range s {
+		v := 0
+		for b := i*5 - 2; b < i*5+3; b++ {
+			v <<= 1
+			if b >= 0 && id[b/8]&(0x80>>uint(b%8)) != 0 {
+				v |= 1
+			}
+		}
+		s[i] = crockford[v]
+	}
+	return string(s[:])
+}
+
 // RelationField instructs to create
 // related record(s) for given Trait's field
 type relationField struct {
@@ -173,6 +292,8 @@ type relationField struct {
 	rfield    string
 	n         int
 	override  Trait
+	// strategy overrides strategy inherited from parent trait (see WithStrategy)
+	strategy Strategy
 }
 
 // CreateRelated is a special Generator that will create related trait
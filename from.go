@@ -0,0 +1,97 @@
+package seedr
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FromOption configures conversion of struct to Trait (see CreateFrom).
+type FromOption func(*fromConfig)
+
+type fromConfig struct {
+	fields []string
+}
+
+// Fields forces inclusion of given struct fields (Go names),
+// even if they have zero values (e.g. false).
+func Fields(names ...string) FromOption {
+	return func(c *fromConfig) {
+		c.fields = append(c.fields, names...)
+	}
+}
+
+// traitFrom converts non-zero fields of struct v (or pointer to struct)
+// to Trait using MapFieldFunc of Seedr. Fields are handled same way as fields of TypedFactory.
+func (sdr *Seedr) traitFrom(v interface{}, opts []FromOption) (Trait, error) {
+	val := reflect.ValueOf(v)
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("struct or pointer to struct is required, %T was given", v)
+	}
+	cfg := fromConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	fields, err := sdr.typedFields(val.Type(), nil)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		known[f.name] = true
+	}
+	forced := make(map[string]bool, len(cfg.fields))
+	for _, name := range cfg.fields {
+		if !known[name] {
+			return nil, fmt.Errorf("field %q of %s can't be used as Trait field", name, val.Type())
+		}
+		forced[name] = true
+	}
+	ret := make(Trait)
+	for _, f := range fields {
+		fv := val.FieldByIndex(f.index)
+		if fv.IsZero() && !forced[f.name] {
+			continue
+		}
+		ret[f.key] = fieldValue(fv)
+	}
+	return ret, nil
+}
+
+// TryCreateFrom creates an instance of trait overridden by non-zero fields of v
+// (struct or pointer to struct) and fields listed in Fields option.
+// Struct fields are mapped using MapFieldFunc (see SetFieldMapper).
+// It uses "create" driver (see SetCreateDriver)
+func (sdr *Seedr) TryCreateFrom(traitName string, v interface{}, opts ...FromOption) (TraitInstance, error) {
+	ovr, err := sdr.traitFrom(v, opts)
+	if err != nil {
+		return TraitInstance{}, err
+	}
+	return sdr.TryCreateCustom(traitName, ovr)
+}
+
+// TryBuildFrom builds trait overridden by fields of v. See TryCreateFrom.
+// It uses "build" driver (see SetBuildDriver)
+func (sdr *Seedr) TryBuildFrom(traitName string, v interface{}, opts ...FromOption) (TraitInstance, error) {
+	ovr, err := sdr.traitFrom(v, opts)
+	if err != nil {
+		return TraitInstance{}, err
+	}
+	return sdr.TryBuildCustom(traitName, ovr)
+}
+
+// CreateFrom is same as TryCreateFrom, but it panics on error.
+func (sdr *Seedr) CreateFrom(traitName string, v interface{}, opts ...FromOption) TraitInstance {
+	ti, err := sdr.TryCreateFrom(traitName, v, opts...)
+	panicOnError(err)
+	return ti
+}
+
+// BuildFrom is same as TryBuildFrom, but it panics on error.
+func (sdr *Seedr) BuildFrom(traitName string, v interface{}, opts ...FromOption) TraitInstance {
+	ti, err := sdr.TryBuildFrom(traitName, v, opts...)
+	panicOnError(err)
+	return ti
+}
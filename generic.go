@@ -0,0 +1,98 @@
+package seedr
+
+// TryCreate creates an instance of trait and scans it into new T (struct).
+// It uses "create" driver (see SetCreateDriver)
+func TryCreate[T any](sdr *Seedr, traitName string) (T, TraitInstance, error) {
+	var v T
+	ti, err := sdr.TryCreate(traitName)
+	if err != nil {
+		return v, ti, err
+	}
+	err = ti.TryScan(&v)
+	return v, ti, err
+}
+
+// TryCreateBatch creates n instances of trait and scans them into new []T.
+// It uses "create" driver (see SetCreateDriver)
+func TryCreateBatch[T any](sdr *Seedr, traitName string, n int) ([]T, *TraitInstances, error) {
+	ins, err := sdr.TryCreateBatch(traitName, n)
+	if err != nil {
+		return nil, ins, err
+	}
+	var v []T
+	err = ins.TryScan(&v)
+	return v, ins, err
+}
+
+// TryBuild builds trait and scans it into new T (struct).
+// It uses "build" driver (see SetBuildDriver)
+func TryBuild[T any](sdr *Seedr, traitName string) (T, TraitInstance, error) {
+	var v T
+	ti, err := sdr.TryBuild(traitName)
+	if err != nil {
+		return v, ti, err
+	}
+	err = ti.TryScan(&v)
+	return v, ti, err
+}
+
+// TryBuildBatch builds n trait instances and scans them into new []T.
+// It uses "build" driver (see SetBuildDriver)
+func TryBuildBatch[T any](sdr *Seedr, traitName string, n int) ([]T, *TraitInstances, error) {
+	ins, err := sdr.TryBuildBatch(traitName, n)
+	if err != nil {
+		return nil, ins, err
+	}
+	var v []T
+	err = ins.TryScan(&v)
+	return v, ins, err
+}
+
+// TryRelated scans instances related to ti by given relation into new []T.
+func TryRelated[T any](ti TraitInstance, relationName string) ([]T, error) {
+	rels := ti.related(relationName)
+	if rels == nil {
+		return nil, ti.noRelationError(relationName)
+	}
+	var v []T
+	if rels.Len() == 0 {
+		return v, nil
+	}
+	err := rels.TryScan(&v)
+	return v, err
+}
+
+// Create is same as TryCreate, but it panics on error.
+func Create[T any](sdr *Seedr, traitName string) (T, TraitInstance) {
+	v, ti, err := TryCreate[T](sdr, traitName)
+	panicOnError(err)
+	return v, ti
+}
+
+// CreateBatch is same as TryCreateBatch, but it panics on error.
+func CreateBatch[T any](sdr *Seedr, traitName string, n int) ([]T, *TraitInstances) {
+	v, ins, err := TryCreateBatch[T](sdr, traitName, n)
+	panicOnError(err)
+	return v, ins
+}
+
+// Build is same as TryBuild, but it panics on error.
+func Build[T any](sdr *Seedr, traitName string) (T, TraitInstance) {
+	v, ti, err := TryBuild[T](sdr, traitName)
+	panicOnError(err)
+	return v, ti
+}
+
+// BuildBatch is same as TryBuildBatch, but it panics on error.
+func BuildBatch[T any](sdr *Seedr, traitName string, n int) ([]T, *TraitInstances) {
+	v, ins, err := TryBuildBatch[T](sdr, traitName, n)
+	panicOnError(err)
+	return v, ins
+}
+
+// Related is same as TryRelated, but it panics on error.
+func Related[T any](ti TraitInstance, relationName string) []T {
+	v, err := TryRelated[T](ti, relationName)
+	panicOnError(err)
+	return v
+}
@@ -0,0 +1,170 @@
+package seedr
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/josephbuchma/seedr/driver"
+)
+
+func (sdr *Seedr) finder() (driver.Finder, error) {
+	f, ok := sdr.createDriver.(driver.Finder)
+	if !ok {
+		return nil, fmt.Errorf("reload: driver %T does not support finding records", sdr.createDriver)
+	}
+	return f, nil
+}
+
+// checkCreated returns ErrNotCreated if instances were not stored by "create" driver,
+// so there is nothing to reload or update.
+func (ti *TraitInstances) checkCreated(op string) error {
+	if ti.strategy != StrategyCreate {
+		return fmt.Errorf("%s: %w: %s was built or stubbed", op, ErrNotCreated, ti.trait.factory.FactoryConfig.Entity)
+	}
+	return nil
+}
+
+// reloadData re-reads given records (subset of ti.data) and updates them in place,
+// so all TraitInstances that share them see new values.
+func (ti *TraitInstances) reloadData(ctx context.Context, f driver.Finder, data []map[string]interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	pk, err := ti.trait.factory.FactoryConfig.pk()
+	if err != nil {
+		return err
+	}
+	fieldSet := make(map[string]bool)
+	for _, rec := range data {
+		if rec[pk] == nil {
+			return fmt.Errorf("reload: %s record has no primary key value", ti.trait.factory.Entity)
+		}
+		for k := range rec {
+			fieldSet[k] = true
+		}
+	}
+	fields := make([]string, 0, len(fieldSet))
+	for k := range fieldSet {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+
+	p := driver.Payload{
+		Entity:       ti.trait.factory.FactoryConfig.Entity,
+		PrimaryKey:   pk,
+		ReturnFields: fields,
+		Data:         data,
+	}
+	recs, err := f.Find(ctx, p)
+	if err != nil {
+		return &DriverError{Entity: p.Entity, Payload: p, Err: err}
+	}
+	if len(recs) != len(data) {
+		return fmt.Errorf("reload: %d records of %s requested, but %d found", len(data), p.Entity, len(recs))
+	}
+	for i, rec := range recs {
+		for k, v := range rec {
+			data[i][k] = v
+		}
+	}
+	return nil
+}
+
+// reload re-reads instances and their related instances.
+// Related instances that were not created (see WithStrategy) are skipped.
+func (ti *TraitInstances) reload(ctx context.Context, f driver.Finder) error {
+	if ti.strategy != StrategyCreate {
+		return nil
+	}
+	if err := ti.reloadData(ctx, f, ti.data); err != nil {
+		return err
+	}
+	for _, p := range ti.parents {
+		if err := p.reload(ctx, f); err != nil {
+			return err
+		}
+	}
+	for _, chs := range ti.childs {
+		for _, ch := range chs {
+			if err := ch.reload(ctx, f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (ti TraitInstance) reload(ctx context.Context, f driver.Finder) error {
+	if ti.insts.strategy != StrategyCreate {
+		return nil
+	}
+	if err := ti.insts.reloadData(ctx, f, ti.insts.data[ti.i:ti.i+1]); err != nil {
+		return err
+	}
+	for _, p := range ti.insts.parents {
+		if err := (TraitInstance{sdr: ti.sdr, insts: p, i: ti.i}).reload(ctx, f); err != nil {
+			return err
+		}
+	}
+	for _, chs := range ti.insts.childs {
+		if len(chs) > ti.i {
+			if err := chs[ti.i].reload(ctx, f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ReloadContext re-reads TraitInstance and all its related instances from storage,
+// so later Scan returns current values.
+// "Create" driver must implement driver.Finder.
+// Built and stubbed instances can't be reloaded (see ErrNotCreated).
+func (ti TraitInstance) ReloadContext(ctx context.Context) error {
+	if err := ti.insts.checkCreated("reload"); err != nil {
+		return err
+	}
+	f, err := ti.sdr.finder()
+	if err != nil {
+		return err
+	}
+	return ti.reload(ctx, f)
+}
+
+// TryReload is same as ReloadContext with context.Background().
+func (ti TraitInstance) TryReload() error {
+	return ti.ReloadContext(context.Background())
+}
+
+// Reload is same as TryReload, but it panics on error.
+func (ti TraitInstance) Reload() TraitInstance {
+	panicOnError(ti.TryReload())
+	return ti
+}
+
+// ReloadContext re-reads all trait instances and their related instances from storage,
+// so later Scan returns current values.
+// "Create" driver must implement driver.Finder.
+// Built and stubbed instances can't be reloaded (see ErrNotCreated).
+func (ti *TraitInstances) ReloadContext(ctx context.Context) error {
+	if err := ti.checkCreated("reload"); err != nil {
+		return err
+	}
+	f, err := ti.sdr.finder()
+	if err != nil {
+		return err
+	}
+	return ti.reload(ctx, f)
+}
+
+// TryReload is same as ReloadContext with context.Background().
+func (ti *TraitInstances) TryReload() error {
+	return ti.ReloadContext(context.Background())
+}
+
+// Reload is same as TryReload, but it panics on error.
+func (ti *TraitInstances) Reload() *TraitInstances {
+	panicOnError(ti.TryReload())
+	return ti
+}
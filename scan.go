@@ -0,0 +1,380 @@
+package seedr
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// scanPlan maps fields of struct type to Trait fields.
+type scanPlan struct {
+	fields []scanField
+}
+
+type scanField struct {
+	// index of struct field (see reflect.Value.FieldByIndex)
+	index []int
+	// name of struct field
+	name string
+	// key is a name of Trait field
+	key string
+	// convert assigns record value to struct field
+	convert converter
+}
+
+// converter assigns src to fv (addressable value of struct field).
+type converter func(fv reflect.Value, src interface{}) error
+
+// converterFor returns converter for struct field of type t.
+// Values are decoded by ValueEncoder registered for t (or for type of *t) if any.
+// Values of exactly same type are assigned directly,
+// other values are converted by convertAssign.
+func converterFor(t reflect.Type, encs valueEncoders) converter {
+	if enc := encs.lookup(t); enc != nil {
+		return func(fv reflect.Value, src interface{}) error {
+			return enc.Decode(src, fv.Addr().Interface())
+		}
+	}
+	if t.Kind() == reflect.Ptr {
+		if enc := encs.lookup(t.Elem()); enc != nil {
+			return func(fv reflect.Value, src interface{}) error {
+				if src == nil {
+					fv.Set(reflect.Zero(t))
+					return nil
+				}
+				p := reflect.New(t.Elem())
+				if err := enc.Decode(src, p.Interface()); err != nil {
+					return err
+				}
+				fv.Set(p)
+				return nil
+			}
+		}
+	}
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Interface || reflect.PtrTo(t).Implements(scannerType) {
+		// []byte must be cloned and Scanner must be called, so convertAssign does it
+		return convertAssignValue
+	}
+	return func(fv reflect.Value, src interface{}) error {
+		if sv := reflect.ValueOf(src); sv.IsValid() && sv.Type() == t {
+			fv.Set(sv)
+			return nil
+		}
+		return convertAssignValue(fv, src)
+	}
+}
+
+func convertAssignValue(fv reflect.Value, src interface{}) error {
+	return convertAssign(fv.Addr().Interface(), src)
+}
+
+// scanPlans caches scanPlan of every scanned type, it's safe for concurrent use.
+// It's not shared with Seedrs derived by With, because they may use different MapFieldFunc.
+type scanPlans struct {
+	m sync.Map
+}
+
+// scanPlan returns cached scanPlan for struct type t, or builds new one.
+func (sdr *Seedr) scanPlan(t reflect.Type) (*scanPlan, error) {
+	if p, ok := sdr.scanPlans.m.Load(t); ok {
+		return p.(*scanPlan), nil
+	}
+	fields, err := sdr.scanFields(t, nil)
+	if err != nil {
+		return nil, err
+	}
+	// if several fields map to same key, shallowest one wins (same as in Go selectors)
+	p := &scanPlan{fields: make([]scanField, 0, len(fields))}
+	byKey := make(map[string]int, len(fields))
+	for _, f := range fields {
+		if i, ok := byKey[f.key]; ok {
+			if len(f.index) < len(p.fields[i].index) {
+				p.fields[i] = f
+			}
+			continue
+		}
+		byKey[f.key] = len(p.fields)
+		p.fields = append(p.fields, f)
+	}
+	sdr.scanPlans.m.Store(t, p)
+	return p, nil
+}
+
+// checkStrict returns *StrictScanError if any field of plan has no matching key in rec,
+// or any key of rec has no matching field. Fields named after relations are ignored.
+func (p *scanPlan) checkStrict(t reflect.Type, rec map[string]interface{}, relations map[string]*Relation) error {
+	var e StrictScanError
+	used := make(map[string]bool, len(p.fields))
+	for _, f := range p.fields {
+		used[f.key] = true
+		if _, ok := rec[f.key]; !ok && relations[f.key] == nil {
+			e.UnmappedFields = append(e.UnmappedFields, fmt.Sprintf("%s (%s)", f.name, f.key))
+		}
+	}
+	for k := range rec {
+		if !used[k] {
+			e.UnusedKeys = append(e.UnusedKeys, k)
+		}
+	}
+	if len(e.UnmappedFields) == 0 && len(e.UnusedKeys) == 0 {
+		return nil
+	}
+	sort.Strings(e.UnusedKeys)
+	e.Type = t.String()
+	return &e
+}
+
+// scanFields returns all fields of struct type t that can be scanned.
+// Fields of embedded structs (and pointers to structs) are flattened.
+func (sdr *Seedr) scanFields(t reflect.Type, index []int) ([]scanField, error) {
+	var ret []scanField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if skip, rel := seedrTag(f); skip || rel != "" {
+			continue
+		}
+		idx := append(index[:len(index):len(index)], i)
+		if et := embeddedStruct(f); et != nil {
+			fields, err := sdr.scanFields(et, idx)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, fields...)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		key, err := sdr.extractFieldName(f)
+		if err != nil {
+			return nil, &ScanError{Field: f.Name, Err: err}
+		}
+		ret = append(ret, scanField{index: idx, name: f.Name, key: key, convert: converterFor(f.Type, sdr.encoders)})
+	}
+	return ret, nil
+}
+
+// embeddedStruct returns type of embedded struct (or pointer to struct)
+// whose fields are flattened, or nil if f is not such field.
+func embeddedStruct(f reflect.StructField) reflect.Type {
+	t := f.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if !f.Anonymous || t.Kind() != reflect.Struct || isScalarStruct(t) {
+		return nil
+	}
+	return t
+}
+
+// fieldByIndex is same as reflect.Value.FieldByIndex, but nil pointers
+// to embedded structs are allocated. Unexported pointers can't be allocated.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("can't set embedded pointer to unexported struct %s", v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// isScalarStruct reports whether struct type t is scanned as single value
+// (time.Time or sql.Scanner), so it's not flattened when embedded.
+func isScalarStruct(t reflect.Type) bool {
+	return t == timeType || reflect.PtrTo(t).Implements(scannerType)
+}
+
+// isMapDest reports whether t is a map that can be a Scan destination
+// (e.g. map[string]interface{} or Trait).
+func isMapDest(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String &&
+		t.Elem().Kind() == reflect.Interface && t.Elem().NumMethod() == 0
+}
+
+// isScanDest reports whether t is a struct or map that can be a Scan destination.
+func isScanDest(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct || isMapDest(t)
+}
+
+// scanMap copies values of record `rec` to map `val`, nil map is initialized.
+func scanMap(val reflect.Value, rec map[string]interface{}) {
+	t := val.Type()
+	if val.IsNil() {
+		val.Set(reflect.MakeMapWithSize(t, len(rec)))
+	}
+	for k, v := range rec {
+		ev := reflect.Zero(t.Elem())
+		if v != nil {
+			ev = reflect.ValueOf(v)
+		}
+		val.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), ev)
+	}
+}
+
+// scanValue initializes `val` (struct or map, see isMapDest) by values of record `rec`.
+func (sdr *Seedr) scanValue(val reflect.Value, rec map[string]interface{}, relations map[string]*Relation) error {
+	if isMapDest(val.Type()) {
+		scanMap(val, rec)
+		return nil
+	}
+	return sdr.scanStruct(val, rec, relations)
+}
+
+// seedrTag parses `seedr` tag of struct field.
+// Supported options are "-" (skip field) and "rel=<relation name>".
+func seedrTag(f reflect.StructField) (skip bool, rel string) {
+	for _, opt := range strings.Split(f.Tag.Get("seedr"), ",") {
+		switch {
+		case opt == "-":
+			skip = true
+		case strings.HasPrefix(opt, "rel="):
+			rel = strings.TrimPrefix(opt, "rel=")
+		}
+	}
+	return skip, rel
+}
+
+// related returns related TraitInstances by relation name, or nil if there are none.
+func (ti TraitInstance) related(relationName string) *TraitInstances {
+	if rels := ti.child(relationName); rels != nil {
+		return rels
+	}
+	return ti.parent(relationName)
+}
+
+// scanDeep scans ti into struct val and recursively fills fields
+// that represent relations (see ScanDeep).
+func (ti TraitInstance) scanDeep(val reflect.Value) error {
+	if err := ti.sdr.scanStruct(val, ti.insts.data[ti.i], ti.insts.trait.relations); err != nil {
+		return err
+	}
+	t := val.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		skip, rel := seedrTag(f)
+		if skip {
+			continue
+		}
+		explicit := rel != ""
+		if !explicit {
+			var err error
+			if rel, err = ti.sdr.extractFieldName(f); err != nil {
+				continue
+			}
+		}
+		rels := ti.related(rel)
+		if rels == nil {
+			if explicit {
+				return &ScanError{Field: f.Name, Key: rel, Err: fmt.Errorf("%q factory has no relation %q", ti.insts.trait.factory.factoryName, rel)}
+			}
+			continue
+		}
+		if err := rels.scanDeepInto(val.Field(i)); err != nil {
+			return &ScanError{Field: f.Name, Key: rel, Err: err}
+		}
+	}
+	return nil
+}
+
+// scanDeepInto scans instances into fv, that is either
+// slice of structs (or pointers to structs), struct or pointer to struct.
+func (ti *TraitInstances) scanDeepInto(fv reflect.Value) error {
+	t := fv.Type()
+	switch {
+	case t.Kind() == reflect.Slice && structOrPtr(t.Elem()):
+		sls := reflect.MakeSlice(t, ti.Len(), ti.Len())
+		for i := 0; i < ti.Len(); i++ {
+			if err := ti.Index(i).scanDeepElem(sls.Index(i)); err != nil {
+				return err
+			}
+		}
+		fv.Set(sls)
+	case structOrPtr(t):
+		switch ti.Len() {
+		case 0:
+			return nil
+		case 1:
+			return ti.Index(0).scanDeepElem(fv)
+		default:
+			return fmt.Errorf("%d related instances can't be scanned into %s", ti.Len(), t)
+		}
+	default:
+		return fmt.Errorf("related instances can't be scanned into %s", t)
+	}
+	return nil
+}
+
+// scanDeepElem scans ti into fv, that is struct or pointer to struct.
+func (ti TraitInstance) scanDeepElem(fv reflect.Value) error {
+	if fv.Kind() == reflect.Ptr {
+		p := reflect.New(fv.Type().Elem())
+		if err := ti.scanDeep(p.Elem()); err != nil {
+			return err
+		}
+		fv.Set(p)
+		return nil
+	}
+	return ti.scanDeep(fv)
+}
+
+func structOrPtr(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// TryScanDeep is same as TryScan, but it also fills struct fields that represent relations
+// created along with TraitInstance, recursively to any depth.
+// Relation is matched by `seedr:"rel=<relation name>"` tag of field,
+// or by name of field mapped by MapFieldFunc (see SetFieldMapper).
+// Field may be a slice of structs (or pointers to structs), struct or pointer to struct.
+// `v` must be a pointer to struct.
+func (ti TraitInstance) TryScanDeep(v interface{}) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("ScanDeep argument must be pointer to struct, %T was given", v)
+	}
+	return ti.scanDeep(val.Elem())
+}
+
+// ScanDeep is same as TryScanDeep, but it panics on error.
+func (ti TraitInstance) ScanDeep(v interface{}) TraitInstance {
+	panicOnError(ti.TryScanDeep(v))
+	return ti
+}
+
+// TryScanDeep is same as TryScan, but it also fills struct fields that represent relations.
+// See TraitInstance.TryScanDeep.
+func (ti *TraitInstances) TryScanDeep(dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("ScanDeep works only with pointers, %T was given", dest)
+	}
+	return ti.scanDeepInto(v.Elem())
+}
+
+// ScanDeep is same as TryScanDeep, but it panics on error.
+func (ti *TraitInstances) ScanDeep(dest interface{}) *TraitInstances {
+	panicOnError(ti.TryScanDeep(dest))
+	return ti
+}
@@ -5,7 +5,10 @@
 package seedr
 
 import (
+	"context"
 	"database/sql"
+	sqldriver "database/sql/driver"
+	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -14,6 +17,7 @@ import (
 
 	"github.com/josephbuchma/seedr/driver"
 	"github.com/josephbuchma/seedr/driver/noop"
+	"github.com/josephbuchma/seedr/driver/stub"
 )
 
 const (
@@ -27,8 +31,12 @@ const (
 // Trait contains factory fields definitions.
 // Key is a field name and value is any of supported:
 //    - any numeric type
+//    - string, []byte, bool
+//    - named types of kinds listed above (e.g. enums)
 //    - time.Time
 //    - sql.Scanner
+//    - driver.Valuer (database/sql/driver)
+//    - values that have ValueEncoder registered (see SetValueEncoder)
 //    - Generator
 //    - nil (interface{}(nil))
 // Special key is `Include` constant
@@ -62,25 +70,88 @@ func (t Trait) merge(trait Trait, override bool) Trait {
 	return t
 }
 
+// generatorError is returned by built-in generators (instead of value)
+// on invalid definition, so getFieldValue fails with it.
+type generatorError struct {
+	err error
+}
+
+// recordScope holds state shared by generators of single record.
+type recordScope map[interface{}]interface{}
+
+// recordGenerator is a Generator that produces values of the same record
+// consistently (e.g. fields of the same struct of TypedFactory).
+type recordGenerator interface {
+	Generator
+	nextInRecord(rec recordScope) interface{}
+}
+
 // getFieldValue bypasses given value if it's of supported type
-// or returns .Next() if it's a Generator
-// otherwise it panics
-func getFieldValue(v interface{}) (interface{}, error) {
+// or returns .Next() if it's a Generator.
+// Values that have ValueEncoder registered in encs are encoded.
+// Otherwise it returns error.
+func getFieldValue(v interface{}, encs valueEncoders) (interface{}, error) {
 	if v == nil {
 		return nil, nil
 	}
+	if enc := encs.types[reflect.TypeOf(v)]; enc != nil {
+		return enc.Encode(v)
+	}
 	switch v := v.(type) {
 	case int, uint, int8, uint8, int16,
 		uint16, int32, uint32, int64, uint64,
 		float32, float64, string, []byte, bool,
-		time.Time, sql.Scanner, auto, *relationField, dependentField:
+		time.Time, sql.Scanner, sqldriver.Valuer, auto, *relationField, dependentField:
 		return v, nil
+	case generatorError:
+		return nil, v.err
 	case Generator:
-		return getFieldValue(v.Next())
+		return getFieldValue(v.Next(), encs)
+	}
+	t := reflect.TypeOf(v)
+	if isBasicKind(t) {
+		return v, nil
+	}
+	if enc := encs.kinds[t.Kind()]; enc != nil {
+		return enc.Encode(v)
 	}
 	return nil, fmt.Errorf("`%v` is of unsupported type %T", v, v)
 }
 
+// recordEval evaluates fields of single record (see field).
+// It's shared by creation (publicTrait.next) and update (nextUpdate) of records.
+type recordEval struct {
+	scope     recordScope
+	encs      valueEncoders
+	rec       map[string]interface{}
+	dependent map[string]dependentField
+}
+
+func newRecordEval(rec map[string]interface{}, dependent map[string]dependentField, encs valueEncoders) recordEval {
+	return recordEval{scope: make(recordScope), encs: encs, rec: rec, dependent: dependent}
+}
+
+// field evaluates value v of field k and returns it.
+// DependsOn fields are added to dependent (they're resolved when other fields are ready),
+// relations and Auto() are only returned, all other values are set in rec.
+func (e recordEval) field(k string, v interface{}) (interface{}, error) {
+	if rg, ok := v.(recordGenerator); ok {
+		v = rg.nextInRecord(e.scope)
+	}
+	fv, err := getFieldValue(v, e.encs)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get value of field %q: %w", k, err)
+	}
+	switch fv := fv.(type) {
+	case *relationField, auto:
+	case dependentField:
+		e.dependent[k] = fv
+	default:
+		e.rec[k] = fv
+	}
+	return fv, nil
+}
+
 // FactoryConfig ...
 type FactoryConfig struct {
 	factoryName string
@@ -92,13 +163,44 @@ type FactoryConfig struct {
 	// It is required if you define any relations.
 	// It also may be required by your Driver.
 	PrimaryKey string
+	// UniqueBy is a list of fields that identify existing record
+	// (e.g. columns of unique index). It's required by FindOrCreate* and Upsert*
+	// methods, which don't create duplicates of records with same values of these fields.
+	UniqueBy []string
+}
+
+// conflict returns Conflict of given config. ConflictUpdate updates insertFields
+// except UniqueBy fields and PrimaryKey (only ones given by UpdateFields, if any).
+func (fc FactoryConfig) conflict(cfg conflictConfig, insertFields []string) *driver.Conflict {
+	c := &driver.Conflict{Strategy: cfg.strategy, Key: fc.UniqueBy}
+	if cfg.strategy != driver.ConflictUpdate {
+		return c
+	}
+	skip := make(map[string]bool, len(fc.UniqueBy)+1)
+	for _, k := range fc.UniqueBy {
+		skip[k] = true
+	}
+	skip[fc.PrimaryKey] = true
+	var only map[string]bool
+	if cfg.onlyUpdate {
+		only = make(map[string]bool, len(cfg.update))
+		for _, f := range cfg.update {
+			only[f] = true
+		}
+	}
+	for _, f := range insertFields {
+		if !skip[f] && (only == nil || only[f]) {
+			c.Update = append(c.Update, f)
+		}
+	}
+	return c
 }
 
-func (fc FactoryConfig) mustPK() string {
+func (fc FactoryConfig) pk() (string, error) {
 	if fc.PrimaryKey == "" {
-		panicf("PrimaryKey for %s is not defined", fc.Entity)
+		return "", fmt.Errorf("PrimaryKey for %s is not defined", fc.Entity)
 	}
-	return fc.PrimaryKey
+	return fc.PrimaryKey, nil
 }
 
 // Factory is a container for factory definition
@@ -164,6 +266,27 @@ func SetBuildDriver(b driver.Driver) ConfigFunc {
 	}
 }
 
+// SetUnitOfWork enables "unit of work" mode, where every top-level
+// Create* call (including all related traits) runs in single transaction
+// and is rolled back entirely on error.
+// "Create" driver must implement driver.Transactor.
+func SetUnitOfWork(enabled bool) ConfigFunc {
+	return func(s *Seedr) {
+		s.unitOfWork = enabled
+	}
+}
+
+// SetStrictScan enables "strict" Scan mode, where Scan fails with *StrictScanError
+// if any struct field has no matching record key, or any record key
+// is not scanned into any struct field (fields are matched using MapFieldFunc).
+// Fields tagged with `seedr:"-"`, unexported fields and fields that represent relations
+// of trait (see ScanDeep) are ignored.
+func SetStrictScan(enabled bool) ConfigFunc {
+	return func(s *Seedr) {
+		s.strictScan = enabled
+	}
+}
+
 // MapFieldFunc returns name of Trait's field based on StructField
 type MapFieldFunc func(reflect.StructField) (traitFieldName string, err error)
 
@@ -231,13 +354,26 @@ type Seedr struct {
 	createDriver driver.Driver
 	// buildDriver is used in Build* methods
 	buildDriver driver.Driver
+	// stubDriver is used in BuildStubbed* methods
+	stubDriver driver.Driver
 	// publicTraits contains map with all traits that starts with capital letter
 	publicTraits     map[string]*publicTrait
 	extractFieldName MapFieldFunc
+	// unitOfWork enables transaction per top-level call
+	unitOfWork bool
+	// session tracks created records, it's set for Seedr of Session
+	session *Session
+	// scanPlans caches mapping of scanned struct types
+	scanPlans *scanPlans
+	// strictScan enables strict Scan mode
+	strictScan bool
+	// encoders encode values of custom types
+	encoders valueEncoders
 }
 
 // New creates Seedr instance with NoopFieldMapper
 // and NoopDriver for create and build methods by default.
+// BuildStubbed* methods always use stub.StubDriver.
 func New(name string, config ...ConfigFunc) *Seedr {
 	sdr := &Seedr{
 		name:             validString(name, "Seedr name can't be empty string"),
@@ -245,6 +381,8 @@ func New(name string, config ...ConfigFunc) *Seedr {
 		extractFieldName: NoopFieldMapper(),
 		createDriver:     noop.NoopDriver{},
 		buildDriver:      noop.NoopDriver{},
+		stubDriver:       stub.New(),
+		scanPlans:        &scanPlans{},
 	}
 	for _, cfg := range config {
 		cfg(sdr)
@@ -252,6 +390,38 @@ func New(name string, config ...ConfigFunc) *Seedr {
 	return sdr
 }
 
+// With returns copy of Seedr with given config applied.
+// Factories added before With are shared by both Seedrs,
+// factories added after are visible only in Seedr they were added to.
+func (sdr *Seedr) With(config ...ConfigFunc) *Seedr {
+	cp := *sdr
+	cp.scanPlans = &scanPlans{}
+	cp.publicTraits = make(map[string]*publicTrait, len(sdr.publicTraits))
+	for name, t := range sdr.publicTraits {
+		pt := *t
+		pt.sdr = &cp
+		cp.publicTraits[name] = &pt
+	}
+	for _, cfg := range config {
+		cfg(&cp)
+	}
+	return &cp
+}
+
+// Begin starts transaction using "create" driver (it must implement driver.Transactor)
+// and returns Seedr that creates records within this transaction.
+func (sdr *Seedr) Begin(ctx context.Context) (*Seedr, driver.Tx, error) {
+	tr, ok := sdr.createDriver.(driver.Transactor)
+	if !ok {
+		return nil, nil, fmt.Errorf("driver %T does not support transactions", sdr.createDriver)
+	}
+	tx, err := tr.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sdr.With(SetCreateDriver(tx)), tx, nil
+}
+
 // Add adds new factory to this seedr
 func (sdr *Seedr) Add(factoryName string, f Factory) *Seedr {
 	f.FactoryConfig.factoryName = factoryName
@@ -288,12 +458,12 @@ func (sdr *Seedr) Add(factoryName string, f Factory) *Seedr {
 	return sdr
 }
 
-func (sdr *Seedr) getPublicTrait(name string) *publicTrait {
+func (sdr *Seedr) getPublicTrait(name string) (*publicTrait, error) {
 	t, ok := sdr.publicTraits[name]
 	if !ok {
-		panicf("Trait %q does not exist", name)
+		return nil, traitNotFound(name)
 	}
-	return t
+	return t, nil
 }
 
 type rawTrait struct {
@@ -334,8 +504,12 @@ func (t *publicTrait) normalizeRelations() {
 	}
 }
 
-func (t *publicTrait) next(n int, ovr Trait) *rawTrait {
+func (t *publicTrait) next(n int, ovr Trait) (*rawTrait, error) {
 	depsReady := false
+	var encs valueEncoders
+	if t.sdr != nil {
+		encs = t.sdr.encoders
+	}
 	rt := &rawTrait{
 		trait:     t,
 		data:      make([]map[string]interface{}, 0, n),
@@ -344,6 +518,7 @@ func (t *publicTrait) next(n int, ovr Trait) *rawTrait {
 
 	for i := 0; i < n; i++ {
 		nxt := make(Trait)
+		eval := newRecordEval(nxt, rt.dependent, encs)
 		for i, trait := range []Trait{t.trait, ovr} {
 			for k, v := range trait {
 				if ovr != nil {
@@ -355,16 +530,9 @@ func (t *publicTrait) next(n int, ovr Trait) *rawTrait {
 						}
 					}
 				}
-				fv, err := getFieldValue(v)
+				fv, err := eval.field(k, v)
 				if err != nil {
-					panicf("Failed to get value of field %q: %s", k, err)
-				}
-				switch fv := fv.(type) {
-				case *relationField, auto:
-				case dependentField:
-					rt.dependent[k] = fv
-				default:
-					nxt[k] = fv
+					return nil, err
 				}
 				if depsReady {
 					continue
@@ -373,14 +541,14 @@ func (t *publicTrait) next(n int, ovr Trait) *rawTrait {
 				case *relationField:
 					if v.lfield == "" {
 						if rel, ok := t.relations[k]; ok {
-							if _, ok := t.sdr.publicTraits[v.traitName]; !ok {
-								panicf("Trait %q does not exist (trait %q, field %q)", v.traitName, t.name, k)
+							if _, err := t.sdr.getPublicTrait(v.traitName); err != nil {
+								return nil, fmt.Errorf("%w (trait %q, field %q)", err, t.name, k)
 							}
 							v.kind = rel.kind
 							v.rfield = rel.rfield
 							v.lfield = rel.lfield
 						} else {
-							panicf("Relation %s is not defined for trait %s", k, t.name)
+							return nil, fmt.Errorf("Relation %s is not defined for trait %s", k, t.name)
 						}
 					}
 					rt.addRel(k, v)
@@ -399,61 +567,132 @@ func (t *publicTrait) next(n int, ovr Trait) *rawTrait {
 
 	}
 	rt.returnFields = append(rt.insertFields, rt.returnFields...)
-	return rt
+	return rt, nil
 }
 
-func (t *publicTrait) build(ovr Trait, n int) (ret *TraitInstances) {
-	return t.drvCreate(ovr, n, t.sdr.buildDriver)
+// createOp holds state of single top-level Create* or Build* call.
+type createOp struct {
+	ctx context.Context
+	sdr *Seedr
+	// tx is a transaction of unit of work (see SetUnitOfWork).
+	// It is started on first use of "create" driver.
+	tx driver.Tx
+	// created contains payloads with results of "create" driver,
+	// they are passed to session by finish.
+	created []driver.Payload
+	// conflict configures FindOrCreate* and Upsert* calls,
+	// it's applied to all traits of factories with UniqueBy.
+	conflict conflictConfig
 }
 
-func (t *publicTrait) create(ovr Trait, n int) (ret *TraitInstances) {
-	return t.drvCreate(ovr, n, t.sdr.createDriver)
+func newCreateOp(ctx context.Context, sdr *Seedr) *createOp {
+	return &createOp{ctx: ctx, sdr: sdr}
 }
 
-func (t *publicTrait) drvCreate(ovr Trait, n int, drv driver.Driver) (ret *TraitInstances) {
-	ret = &TraitInstances{sdr: t.sdr, trait: t, childs: make(map[string][]*TraitInstances)}
-	rt := t.next(n, ovr)
-	if rt.rels != nil {
-		ret.parents = make(map[string]*TraitInstances)
-		childs := make(map[string]*relationField)
-		m2ms := make(map[string]*relationField)
+// createDriver returns "create" driver, or transaction in unit of work mode.
+func (o *createOp) createDriver() (driver.Driver, error) {
+	if !o.sdr.unitOfWork {
+		return o.sdr.createDriver, nil
+	}
+	if o.tx == nil {
+		tr, ok := o.sdr.createDriver.(driver.Transactor)
+		if !ok {
+			return nil, fmt.Errorf("unit of work: driver %T does not support transactions", o.sdr.createDriver)
+		}
+		tx, err := tr.Begin(o.ctx)
+		if err != nil {
+			return nil, err
+		}
+		o.tx = tx
+	}
+	return o.tx, nil
+}
 
-		// handle child and many to many relations after this one is created
-		defer func() {
-			if len(childs) == 0 && len(m2ms) == 0 {
-				return
-			}
-			pks := make([]interface{}, len(ret.data))
-			pkName := t.factory.FactoryConfig.mustPK()
-			for i, r := range ret.data {
-				pks[i] = r[pkName]
-			}
-			for field, rel := range childs {
-				ins := t.sdr.getPublicTrait(rel.traitName).create((Trait{
-					rel.lfield: mnSliceSeq(pks, rel.n),
-				}).merge(rel.override, false), len(rt.data)*rel.n)
-				ret.childs[field] = ins.chop(n)
-			}
-			for field, rel := range m2ms {
-				rels := t.sdr.getPublicTrait(rel.traitName).create(rel.override, len(rt.data)*rel.n)
-				relpks := make([]interface{}, rels.Len())
-				for i, r := range rels.data {
-					relpks[i] = r[pkName]
-				}
-				joinTraitName := t.relations[field].joinTrait
-				// TODO: relate join table
-				t.sdr.getPublicTrait(joinTraitName).create(Trait{
-					rel.lfield: mnSliceSeq(pks, rel.n),
-					rel.rfield: mnSliceSeq(relpks, rel.n),
-				}, len(rt.data)*rel.n)
-				ret.childs[field] = rels.chop(n)
-			}
-		}()
+// finish commits transaction of unit of work, or rolls it back if err is not nil.
+func (o *createOp) finish(err error) error {
+	if o.tx != nil {
+		if err != nil {
+			o.tx.Rollback()
+			return err
+		}
+		if err = o.tx.Commit(); err != nil {
+			return err
+		}
+	}
+	if o.sdr.session != nil {
+		o.sdr.session.track(o.created...)
+	}
+	return err
+}
+
+// track adds created records of payload p to o.created.
+// Upserted records that existed before (found) are not tracked,
+// so Session.Cleanup doesn't delete them.
+func (o *createOp) track(p driver.Payload, created []map[string]interface{}, found []bool) {
+	p.Conflict = nil
+	p.Data = nil
+	for i, rec := range created {
+		if found != nil && found[i] {
+			continue
+		}
+		p.Data = append(p.Data, rec)
+	}
+	if len(p.Data) == 0 {
+		return
+	}
+	for _, rec := range p.Data {
+		if rec[p.PrimaryKey] == nil {
+			// primary key is not returned, so records are identified by all fields
+			p.PrimaryKey = ""
+			break
+		}
+	}
+	o.created = append(o.created, p)
+}
 
+// create creates n instances of public trait with given name using given strategy
+func (o *createOp) create(s Strategy, traitName string, n int, ovr Trait) (*TraitInstances, error) {
+	t, err := o.sdr.getPublicTrait(traitName)
+	if err != nil {
+		return nil, err
+	}
+	return t.create(o, s, ovr, n)
+}
+
+// create creates n instances using driver of given strategy.
+func (t *publicTrait) create(o *createOp, s Strategy, ovr Trait, n int) (*TraitInstances, error) {
+	switch s {
+	case StrategyBuild:
+		return t.drvCreate(o, s, ovr, n, t.sdr.buildDriver)
+	case StrategyStub:
+		return t.drvCreate(o, s, ovr, n, t.sdr.stubDriver)
+	}
+	drv, err := o.createDriver()
+	if err != nil {
+		return nil, err
+	}
+	return t.drvCreate(o, s, ovr, n, drv)
+}
+
+// drvCreate creates n instances using drv. Related traits inherit strategy s,
+// unless relation has its own (see WithStrategy).
+func (t *publicTrait) drvCreate(o *createOp, s Strategy, ovr Trait, n int, drv driver.Driver) (*TraitInstances, error) {
+	ret := &TraitInstances{sdr: t.sdr, trait: t, strategy: s, childs: make(map[string][]*TraitInstances)}
+	rt, err := t.next(n, ovr)
+	if err != nil {
+		return nil, err
+	}
+	childs := make(map[string]*relationField)
+	m2ms := make(map[string]*relationField)
+	if rt.rels != nil {
+		ret.parents = make(map[string]*TraitInstances)
 		for field, rel := range rt.rels {
 			switch rel.kind {
 			case relationParent:
-				ins := t.sdr.getPublicTrait(rel.traitName).create(rel.override, len(rt.data))
+				ins, err := t.createRelated(o, s, rel, rel.override, len(rt.data))
+				if err != nil {
+					return nil, err
+				}
 				ret.parents[field] = ins
 			case relationChild:
 				childs[field] = rel
@@ -468,7 +707,11 @@ func (t *publicTrait) drvCreate(ovr Trait, n int, drv driver.Driver) (ret *Trait
 						continue
 					}
 					p := ret.parents[relation]
-					d[rel.lfield] = p.data[i][p.trait.factory.FactoryConfig.mustPK()]
+					pk, err := p.trait.factory.FactoryConfig.pk()
+					if err != nil {
+						return nil, err
+					}
+					d[rel.lfield] = p.data[i][pk]
 				}
 			}
 		}
@@ -476,31 +719,143 @@ func (t *publicTrait) drvCreate(ovr Trait, n int, drv driver.Driver) (ret *Trait
 
 	if len(rt.dependent) > 0 {
 		for _, ti := range rt.data {
-			resolveDependentFields(ti, rt.dependent)
+			if err := resolveDependentFields(ti, rt.dependent); err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	var err error
-	ret.data, err = drv.Create(driver.Payload{
+	var found []bool
+	p := driver.Payload{
 		Entity:       t.factory.FactoryConfig.Entity,
 		PrimaryKey:   t.factory.FactoryConfig.PrimaryKey,
 		InsertFields: rt.insertFields,
 		ReturnFields: rt.returnFields,
 		Data:         rt.data,
-	})
+	}
+	if s == StrategyCreate && o.conflict.strategy != 0 && len(t.factory.FactoryConfig.UniqueBy) > 0 {
+		p.Conflict = t.factory.FactoryConfig.conflict(o.conflict, p.InsertFields)
+		up, ok := drv.(driver.Upserter)
+		if !ok {
+			return nil, fmt.Errorf("driver %T does not support upsert", drv)
+		}
+		ret.data, found, err = up.Upsert(o.ctx, p)
+	} else {
+		ret.data, err = driver.CreateContext(o.ctx, drv, p)
+	}
 	if err != nil {
-		panicf("Seedr Driver error: %s", err)
+		return nil, &DriverError{Entity: p.Entity, Payload: p, Err: err}
 	}
-	return ret
+	if s == StrategyCreate {
+		o.track(p, ret.data, found)
+	}
+
+	// handle child and many to many relations after this one is created
+	if len(childs) == 0 && len(m2ms) == 0 {
+		return ret, nil
+	}
+	pkName, err := t.factory.FactoryConfig.pk()
+	if err != nil {
+		return nil, err
+	}
+	// related records of upserted records that existed before are not created again,
+	// so FindOrCreate* and Upsert* don't create duplicates of them
+	var idx []int
+	var pks []interface{}
+	for i, r := range ret.data {
+		if found == nil || !found[i] {
+			idx = append(idx, i)
+			pks = append(pks, r[pkName])
+		}
+	}
+	for field, rel := range childs {
+		if len(idx) == 0 {
+			if ret.childs[field], err = t.emptyRelated(s, rel, n); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		ins, err := t.createRelated(o, s, rel, (Trait{
+			rel.lfield: mnSliceSeq(pks, rel.n),
+		}).merge(rel.override, false), len(idx)*rel.n)
+		if err != nil {
+			return nil, err
+		}
+		if ret.childs[field], err = ins.spread(idx, n); err != nil {
+			return nil, err
+		}
+	}
+	for field, rel := range m2ms {
+		if len(idx) == 0 {
+			if ret.childs[field], err = t.emptyRelated(s, rel, n); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		rels, err := t.createRelated(o, s, rel, rel.override, len(idx)*rel.n)
+		if err != nil {
+			return nil, err
+		}
+		relPkName, err := rels.trait.factory.FactoryConfig.pk()
+		if err != nil {
+			return nil, err
+		}
+		relpks := make([]interface{}, rels.Len())
+		for i, r := range rels.data {
+			relpks[i] = r[relPkName]
+		}
+		joinTraitName := t.relations[field].joinTrait
+		joinTrait, err := t.sdr.getPublicTrait(joinTraitName)
+		if err != nil {
+			return nil, err
+		}
+		// TODO: relate join table
+		_, err = joinTrait.create(o, rel.strategy.inherit(s), Trait{
+			rel.lfield: mnSliceSeq(pks, rel.n),
+			// every join record refers to its own related record
+			rel.rfield: mnSliceSeq(relpks, 1),
+		}, len(idx)*rel.n)
+		if err != nil {
+			return nil, err
+		}
+		if ret.childs[field], err = rels.spread(idx, n); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// createRelated creates n instances of trait of given relation field
+// using strategy of relation or s (strategy of this trait).
+func (t *publicTrait) createRelated(o *createOp, s Strategy, rel *relationField, ovr Trait, n int) (*TraitInstances, error) {
+	rt, err := t.sdr.getPublicTrait(rel.traitName)
+	if err != nil {
+		return nil, err
+	}
+	return rt.create(o, rel.strategy.inherit(s), ovr, n)
+}
+
+// emptyRelated returns list of n empty instances of trait of given relation field.
+func (t *publicTrait) emptyRelated(s Strategy, rel *relationField, n int) ([]*TraitInstances, error) {
+	rt, err := t.sdr.getPublicTrait(rel.traitName)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*TraitInstances, n)
+	for i := range ret {
+		ret[i] = &TraitInstances{sdr: t.sdr, trait: rt, strategy: rel.strategy.inherit(s),
+			parents: make(map[string]*TraitInstances)}
+	}
+	return ret, nil
 }
 
 func resolveDependentField(f string, ti map[string]interface{}, dep map[string]dependentField,
-	resolved map[string]bool, stack []string) {
+	resolved map[string]bool, stack []string) error {
 	if len(stack) > 0 {
 		for i := len(stack) - 1; i >= 0; i-- {
 			if stack[i] == f {
 				circle := strings.Join(append(stack[i:], f), " -> ")
-				panicf("Circular field dependency: %s", circle)
+				return fmt.Errorf("Circular field dependency: %s", circle)
 			}
 		}
 	}
@@ -508,19 +863,26 @@ func resolveDependentField(f string, ti map[string]interface{}, dep map[string]d
 	d := dep[f]
 	for _, df := range d.fields {
 		if _, ok := dep[df]; ok && !resolved[df] {
-			resolveDependentField(df, ti, dep, resolved, stack)
+			if err := resolveDependentField(df, ti, dep, resolved, stack); err != nil {
+				return err
+			}
 		}
 	}
 	ti[f] = d.do(ti)
-	stack = stack[0 : len(stack)-1]
+	return nil
 }
 
-func resolveDependentFields(ti map[string]interface{}, dep map[string]dependentField) {
+// resolveDependentFields sets values of dependent fields of ti.
+// It returns error on circular dependency.
+func resolveDependentFields(ti map[string]interface{}, dep map[string]dependentField) error {
 	resolved := map[string]bool{}
 	stack := []string{}
 	for f := range dep {
-		resolveDependentField(f, ti, dep, resolved, stack)
+		if err := resolveDependentField(f, ti, dep, resolved, stack); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 // TraitInstance is a created trait instance
@@ -531,108 +893,174 @@ type TraitInstance struct {
 }
 
 // createRelated creates related traits. Works for child and M2M relations.
-func (ti TraitInstance) createRelated(relation, traitName string, n int, override Trait) TraitInstance {
+func (ti TraitInstance) createRelated(relation, traitName string, n int, override Trait) (TraitInstance, error) {
+	o := newCreateOp(context.Background(), ti.sdr)
+	ins, err := ti.createRelatedOp(o, relation, traitName, n, override)
+	if err = o.finish(err); err != nil {
+		return ti, err
+	}
+	if recs, ok := ti.insts.childs[relation]; ok && len(recs) > ti.i {
+		recs[ti.i].append(ins)
+	} else if !ok {
+		ti.insts.childs[relation] = []*TraitInstances{ins}
+	} else if len(recs) == ti.i {
+		ti.insts.childs[relation] = append(ti.insts.childs[relation], ins)
+	} else {
+		return ti, fmt.Errorf("related instances of %q are missing for instances before %d", relation, ti.i)
+	}
+	return ti, nil
+}
+
+func (ti TraitInstance) createRelatedOp(o *createOp, relation, traitName string, n int, override Trait) (*TraitInstances, error) {
 	var ins *TraitInstances
 	rel, ok := ti.insts.trait.relations[relation]
 	if !ok {
-		panicf("%q does not have relation %q", ti.insts.trait.factory.FactoryConfig.factoryName, relation)
+		return nil, fmt.Errorf("%q does not have relation %q", ti.insts.trait.factory.FactoryConfig.factoryName, relation)
+	}
+	pk, err := ti.insts.trait.factory.FactoryConfig.pk()
+	if err != nil {
+		return nil, err
 	}
 	switch rel.kind {
 	case relationChild:
 		ovr := Trait{
-			rel.lfield: ti.insts.data[ti.i][ti.insts.trait.factory.FactoryConfig.mustPK()],
+			rel.lfield: ti.insts.data[ti.i][pk],
 		}
 		if override != nil {
 			ovr = override.merge(ovr, true)
 		}
-		ins = ti.sdr.CreateCustomBatch(traitName, n, ovr)
+		ins, err = o.create(ti.insts.strategy, traitName, n, ovr)
+		if err != nil {
+			return nil, err
+		}
 	case relationM2M:
-		if nm := ti.sdr.getPublicTrait(traitName).factory.FactoryConfig.factoryName; nm != rel.factory {
-			panicf("Invalid M2M: expected factory %s, got %s", rel.factory, nm)
+		t, err := ti.sdr.getPublicTrait(traitName)
+		if err != nil {
+			return nil, err
+		}
+		if nm := t.factory.FactoryConfig.factoryName; nm != rel.factory {
+			return nil, fmt.Errorf("Invalid M2M: expected factory %s, got %s", rel.factory, nm)
+		}
+		relPK, err := t.factory.FactoryConfig.pk()
+		if err != nil {
+			return nil, err
+		}
+		ins, err = o.create(ti.insts.strategy, traitName, n, override)
+		if err != nil {
+			return nil, err
 		}
-		ins = ti.sdr.CreateCustomBatch(traitName, n, override)
 		// TODO: bind 'parent' rel to both
-		ti.sdr.CreateCustomBatch(rel.joinTrait, n, Trait{
-			rel.lfield: ti.insts.data[0][ti.insts.trait.factory.FactoryConfig.mustPK()],
+		_, err = o.create(ti.insts.strategy, rel.joinTrait, n, Trait{
+			rel.lfield: ti.insts.data[0][pk],
 			rel.rfield: SequenceFunc(func(i int) interface{} {
-				return ins.data[i][ins.trait.factory.FactoryConfig.mustPK()]
+				return ins.data[i][relPK]
 			}, 0),
 		})
+		if err != nil {
+			return nil, err
+		}
 	case relationParent:
-		panicf("TraitInstance#CreateRelated does not support 'parent' relations. Please use 'Seedr#CreateCustom' or define trait in factory")
+		return nil, errors.New("TraitInstance#CreateRelated does not support 'parent' relations. Please use 'Seedr#CreateCustom' or define trait in factory")
 	default:
-		panic("UNREACHABLE")
-	}
-	if recs, ok := ti.insts.childs[relation]; ok && len(recs) > ti.i {
-		recs[ti.i].append(ins)
-	} else if !ok {
-		ti.insts.childs[relation] = []*TraitInstances{ins}
-	} else if len(recs) == ti.i {
-		ti.insts.childs[relation] = append(ti.insts.childs[relation], ins)
-	} else {
-		panic("NOOP")
+		return nil, fmt.Errorf("unknown kind of relation %q", relation)
 	}
+	return ins, nil
+}
+
+func (ti TraitInstance) mustCreateRelated(relation, traitName string, n int, override Trait) TraitInstance {
+	ti, err := ti.createRelated(relation, traitName, n, override)
+	panicOnError(err)
 	return ti
 }
 
 // CreateRelated creates single related instance. Works for FK (child only) and M2M.
 // It returns original TraitInstance, not one that was created. Fetch created one using Related method.
 func (ti TraitInstance) CreateRelated(relation, traitName string) TraitInstance {
-	return ti.createRelated(relation, traitName, 1, nil)
+	return ti.mustCreateRelated(relation, traitName, 1, nil)
 }
 
 // CreateRelatedCustom creates single related instance with additional changes. Works for FK (child only) and M2M
 // It returns original TraitInstance, not one that was created. Fetch created one using Related method.
 func (ti TraitInstance) CreateRelatedCustom(relation, traitName string, overrides Trait) TraitInstance {
-	return ti.createRelated(relation, traitName, 1, overrides)
+	return ti.mustCreateRelated(relation, traitName, 1, overrides)
 }
 
 // CreateRelatedBatch creates n related instances. Works for FK (child only) and M2M
 // It returns original TraitInstance, not one that was created. Fetch created one using Related method.
 func (ti TraitInstance) CreateRelatedBatch(relation, traitName string, n int) TraitInstance {
-	return ti.createRelated(relation, traitName, n, nil)
+	return ti.mustCreateRelated(relation, traitName, n, nil)
 }
 
 // CreateRelatedCustomBatch creates n related instances with additional changes. Works for FK (child only) and M2M
 // It returns original TraitInstance, not one that was created. Fetch created one using Related method.
 func (ti TraitInstance) CreateRelatedCustomBatch(relation, traitName string, n int, overrides Trait) TraitInstance {
-	return ti.createRelated(relation, traitName, n, overrides)
+	return ti.mustCreateRelated(relation, traitName, n, overrides)
 }
 
-// Scan initializes given struct instance `v` by TraitInstance's values.
-// `v` must be a pointer to struct.
-func (ti TraitInstance) Scan(v interface{}) TraitInstance {
-	val := reflect.ValueOf(v).Elem()
-	t := val.Type()
-	rec := ti.insts.data[ti.i]
-	scannedCnt := 0
-	for i := 0; i < t.NumField(); i++ {
-		f := t.Field(i)
-		fname, err := ti.sdr.extractFieldName(f)
-		if err != nil {
-			panicf("Failed to Scan: %s", err)
-		}
-		if iv, ok := rec[fname]; !ok {
+// scanStruct initializes struct `val` by values of record `rec`.
+// Fields named after relations of trait are ignored by strict mode.
+func (sdr *Seedr) scanStruct(val reflect.Value, rec map[string]interface{}, relations map[string]*Relation) error {
+	plan, err := sdr.scanPlan(val.Type())
+	if err != nil {
+		return err
+	}
+	for _, f := range plan.fields {
+		iv, ok := rec[f.key]
+		if !ok {
 			continue
-		} else {
-			if err := convertAssign(val.Field(i).Addr().Interface(), iv); err != nil {
-				panic(err)
-			}
-			scannedCnt++
 		}
+		fv, err := fieldByIndex(val, f.index)
+		if err == nil {
+			err = f.convert(fv, iv)
+		}
+		if err != nil {
+			return &ScanError{Field: f.name, Key: f.key, Err: err}
+		}
+	}
+	if sdr.strictScan {
+		return plan.checkStrict(val.Type(), rec, relations)
 	}
-	if len(rec) < scannedCnt {
-		panicf("Could not scan some values from this object: %#v", ti.insts.data[ti.i])
+	return nil
+}
+
+// TryScan initializes given struct instance `v` by TraitInstance's values.
+// `v` must be a pointer to struct or to map[string]interface{}.
+func (ti TraitInstance) TryScan(v interface{}) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Ptr || val.IsNil() || !isScanDest(val.Elem().Type()) {
+		return fmt.Errorf("Scan argument must be pointer to struct or map[string]interface{}, %T was given", v)
 	}
+	return ti.sdr.scanValue(val.Elem(), ti.insts.data[ti.i], ti.insts.trait.relations)
+}
+
+// Scan initializes given struct instance `v` by TraitInstance's values.
+// `v` must be a pointer to struct.
+func (ti TraitInstance) Scan(v interface{}) TraitInstance {
+	panicOnError(ti.TryScan(v))
 	return ti
 }
 
-// ScanRelated scans related TraitInstance(s) into v and returns this TraitInstance.
+// TryScanRelated scans related TraitInstance(s) into v (see TryScan).
+// It returns error if factory has no such relation.
+func (ti TraitInstance) TryScanRelated(relationName string, v interface{}) error {
+	rels := ti.related(relationName)
+	if rels == nil {
+		return ti.noRelationError(relationName)
+	}
+	return rels.TryScan(v)
+}
+
+// ScanRelated is same as TryScanRelated, but it panics on error.
+// It returns this TraitInstance.
 func (ti TraitInstance) ScanRelated(relationName string, v interface{}) TraitInstance {
-	ti.Related(relationName).Scan(v)
+	panicOnError(ti.TryScanRelated(relationName, v))
 	return ti
 }
 
+func (ti TraitInstance) noRelationError(relationName string) error {
+	return fmt.Errorf("%q factory has no relation %q", ti.insts.trait.factory.factoryName, relationName)
+}
+
 // parent returns parent TraitInstance by given FIELD (FK) name.
 func (ti TraitInstance) parent(field string) *TraitInstances {
 	parents, ok := ti.insts.parents[field]
@@ -652,14 +1080,12 @@ func (ti TraitInstance) child(relationName string) *TraitInstances {
 	return childs[ti.i]
 }
 
-// Related returns related TraitInstances (that was created by CreateRelated*)
+// Related returns related TraitInstances (that was created by CreateRelated*).
+// It panics if factory has no such relation (see TryScanRelated).
 func (ti TraitInstance) Related(relationName string) *TraitInstances {
-	rels := ti.child(relationName)
-	if rels == nil {
-		rels = ti.parent(relationName)
-	}
+	rels := ti.related(relationName)
 	if rels == nil {
-		panicf("%q factory has no relation %q", ti.insts.trait.factory.factoryName, relationName)
+		panic(ti.noRelationError(relationName))
 	}
 	return rels
 }
@@ -668,6 +1094,8 @@ func (ti TraitInstance) Related(relationName string) *TraitInstances {
 type TraitInstances struct {
 	sdr   *Seedr
 	trait *publicTrait
+	// strategy is used to create related traits by CreateRelated*
+	strategy Strategy
 	// recs is a list of raw inserted records
 	data []map[string]interface{}
 	// parents is field -> relation[i] for recs[i]
@@ -692,8 +1120,9 @@ func (ti *TraitInstances) append(r *TraitInstances) {
 
 func (ti *TraitInstances) slice(b, e int) *TraitInstances {
 	ret := &TraitInstances{
-		sdr:   ti.sdr,
-		trait: ti.trait,
+		sdr:      ti.sdr,
+		trait:    ti.trait,
+		strategy: ti.strategy,
 		// recs is a list of raw inserted records
 		data:    ti.data[b:e],
 		parents: make(map[string]*TraitInstances),
@@ -710,16 +1139,34 @@ func (ti *TraitInstances) slice(b, e int) *TraitInstances {
 	return ret
 }
 
-func (ti *TraitInstances) chop(n int) []*TraitInstances {
+// chop splits ti into n equal parts.
+func (ti *TraitInstances) chop(n int) ([]*TraitInstances, error) {
 	if len(ti.data)%n != 0 {
-		panic("Can't chop")
+		return nil, fmt.Errorf("can't split %d instances of %q into %d equal parts", len(ti.data), ti.trait.name, n)
 	}
 	ret := make([]*TraitInstances, n)
 	chunk := len(ti.data) / n
 	for i := 0; i < n; i++ {
 		ret[i] = ti.slice(i*chunk, i*chunk+chunk)
 	}
-	return ret
+	return ret, nil
+}
+
+// spread chops ti into len(idx) equal parts (see chop) and returns list of n
+// instances, where list[idx[j]] is j-th part and others are empty.
+func (ti *TraitInstances) spread(idx []int, n int) ([]*TraitInstances, error) {
+	parts, err := ti.chop(len(idx))
+	if err != nil || len(idx) == n {
+		return parts, err
+	}
+	ret := make([]*TraitInstances, n)
+	for i := range ret {
+		ret[i] = ti.slice(0, 0)
+	}
+	for j, i := range idx {
+		ret[i] = parts[j]
+	}
+	return ret, nil
 }
 
 // Len returns total count of trait instances in this collection
@@ -745,125 +1192,328 @@ func (ti *TraitInstances) ScanRelated(relationName string, dest interface{}) {
 	}
 }
 
-// Scan initializes given list of struct instances `dest` by TraitInstance's values.
-// `v` must be a pointer to slice of structs.
-// But, if there is only one instance, dest can be pointer to struct.
-func (ti *TraitInstances) Scan(dest interface{}) (ret *TraitInstances) {
+// TryScan initializes given list of struct instances `dest` by TraitInstance's values.
+// `v` must be a pointer to slice of structs, pointers to structs or map[string]interface{}.
+// But, if there is only one instance, dest can be pointer to struct (or map).
+func (ti *TraitInstances) TryScan(dest interface{}) error {
 	if ti.Len() == 0 {
-		panic("Nothing to Scan")
+		return errors.New("Nothing to Scan")
 	}
 	v := reflect.ValueOf(dest)
 	t := v.Type()
 	if t.Kind() != reflect.Ptr {
-		panicf("Scan works only with pointers, %s was given.", t.Kind())
+		return fmt.Errorf("Scan works only with pointers, %s was given.", t.Kind())
 	}
-	if ti.Len() == 1 {
-		if t.Elem().Kind() == reflect.Struct {
-			ti.Index(0).Scan(dest)
-			return ti
-		}
+	if ti.Len() == 1 && isScanDest(t.Elem()) {
+		return ti.Index(0).TryScan(dest)
 	}
-	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Slice {
-		panicf("InsertedRecords#Scan argument must be pointer to slice")
+	if t.Elem().Kind() != reflect.Slice {
+		return errors.New("InsertedRecords#Scan argument must be pointer to slice")
 	}
 
 	t = t.Elem().Elem()
-	sls := reflect.MakeSlice(reflect.SliceOf(t), len(ti.data), len(ti.data))
-	v.Elem().Set(sls)
-
-	var tPtr reflect.Type
-	if t.Kind() == reflect.Ptr {
-		tPtr = t
+	isPtr := t.Kind() == reflect.Ptr
+	if isPtr {
 		t = t.Elem()
 	}
-
+	if !isScanDest(t) {
+		return fmt.Errorf("Can't Scan into slice of %s", v.Type().Elem().Elem())
+	}
+	sls := reflect.MakeSlice(v.Type().Elem(), len(ti.data), len(ti.data))
 	for i := 0; i < len(ti.data); i++ {
-		vi := sls.Index(i)
-		val := vi
-		if tPtr != nil {
-			val = reflect.Zero(t)
-			vi.Set(val.Addr())
-		}
-		scannedCnt := 0
-		for fid := 0; fid < t.NumField(); fid++ {
-			f := t.Field(fid)
-			fname, err := ti.sdr.extractFieldName(f)
-			if err != nil {
-				panicf("Failed to Scan: %s", err)
-			}
-			if iv, ok := ti.data[i][fname]; !ok {
-				continue
-			} else {
-				if err := convertAssign(val.Field(fid).Addr().Interface(), iv); err != nil {
-					panic(err)
-				}
-				scannedCnt++
-			}
+		val := sls.Index(i)
+		if isPtr {
+			val.Set(reflect.New(t))
+			val = val.Elem()
 		}
-		if len(ti.data[i]) < scannedCnt {
-			panicf("Could not scan some values: %#v", ti.data[i])
+		if err := ti.sdr.scanValue(val, ti.data[i], ti.trait.relations); err != nil {
+			return err
 		}
 	}
+	v.Elem().Set(sls)
+	return nil
+}
+
+// Scan initializes given list of struct instances `dest` by TraitInstance's values.
+// `v` must be a pointer to slice of structs.
+// But, if there is only one instance, dest can be pointer to struct.
+func (ti *TraitInstances) Scan(dest interface{}) (ret *TraitInstances) {
+	panicOnError(ti.TryScan(dest))
 	return ti
 }
 
+// CreateCustomContext overrides values of trait definition and creates resulting trait.
+// Creation of related traits stops as soon as ctx is done.
+// It uses "create" driver (see SetCreateDriver)
+func (sdr *Seedr) CreateCustomContext(ctx context.Context, traitName string, override Trait) (TraitInstance, error) {
+	ins, err := sdr.CreateCustomBatchContext(ctx, traitName, 1, override)
+	if err != nil {
+		return TraitInstance{}, err
+	}
+	return ins.Index(0), nil
+}
+
+// CreateCustomBatchContext overrides values of trait definition
+// and creates n instances of resulting trait.
+// Creation of related traits stops as soon as ctx is done.
+// It uses "create" driver (see SetCreateDriver)
+func (sdr *Seedr) CreateCustomBatchContext(ctx context.Context, traitName string, n int, override Trait) (*TraitInstances, error) {
+	o := newCreateOp(ctx, sdr)
+	ins, err := o.create(StrategyCreate, traitName, n, override)
+	if err = o.finish(err); err != nil {
+		return nil, err
+	}
+	return ins, nil
+}
+
+// CreateBatchContext creates n instances of trait
+// Creation of related traits stops as soon as ctx is done.
+// It uses "create" driver (see SetCreateDriver)
+func (sdr *Seedr) CreateBatchContext(ctx context.Context, traitName string, n int) (*TraitInstances, error) {
+	return sdr.CreateCustomBatchContext(ctx, traitName, n, nil)
+}
+
+// CreateContext creates an instance of trait
+// Creation of related traits stops as soon as ctx is done.
+// It uses "create" driver (see SetCreateDriver)
+func (sdr *Seedr) CreateContext(ctx context.Context, traitName string) (TraitInstance, error) {
+	return sdr.CreateCustomContext(ctx, traitName, nil)
+}
+
+// BuildCustomContext builds trait with additional changes.
+// It uses "build" driver (see SetBuildDriver)
+func (sdr *Seedr) BuildCustomContext(ctx context.Context, traitName string, override Trait) (TraitInstance, error) {
+	ins, err := sdr.BuildCustomBatchContext(ctx, traitName, 1, override)
+	if err != nil {
+		return TraitInstance{}, err
+	}
+	return ins.Index(0), nil
+}
+
+// BuildCustomBatchContext builds n trait instances with additional changes.
+// It uses "build" driver (see SetBuildDriver)
+func (sdr *Seedr) BuildCustomBatchContext(ctx context.Context, traitName string, n int, override Trait) (*TraitInstances, error) {
+	o := newCreateOp(ctx, sdr)
+	ins, err := o.create(StrategyBuild, traitName, n, override)
+	if err = o.finish(err); err != nil {
+		return nil, err
+	}
+	return ins, nil
+}
+
+// BuildBatchContext builds n trait instances.
+// It uses "build" driver (see SetBuildDriver)
+func (sdr *Seedr) BuildBatchContext(ctx context.Context, traitName string, n int) (*TraitInstances, error) {
+	return sdr.BuildCustomBatchContext(ctx, traitName, n, nil)
+}
+
+// BuildContext builds trait.
+// It uses "build" driver (see SetBuildDriver)
+func (sdr *Seedr) BuildContext(ctx context.Context, traitName string) (TraitInstance, error) {
+	return sdr.BuildCustomContext(ctx, traitName, nil)
+}
+
+// TryCreateCustom overrides values of trait definition and creates resulting trait.
+// It uses "create" driver (see SetCreateDriver)
+func (sdr *Seedr) TryCreateCustom(traitName string, override Trait) (TraitInstance, error) {
+	return sdr.CreateCustomContext(context.Background(), traitName, override)
+}
+
+// TryCreateCustomBatch overrides values of trait definition
+// and creates n instances of resulting trait.
+// It uses "create" driver (see SetCreateDriver)
+func (sdr *Seedr) TryCreateCustomBatch(traitName string, n int, override Trait) (*TraitInstances, error) {
+	return sdr.CreateCustomBatchContext(context.Background(), traitName, n, override)
+}
+
+// TryCreateBatch creates n instances of trait
+// It uses "create" driver (see SetCreateDriver)
+func (sdr *Seedr) TryCreateBatch(traitName string, n int) (*TraitInstances, error) {
+	return sdr.CreateCustomBatchContext(context.Background(), traitName, n, nil)
+}
+
+// TryCreate creates an instance of trait
+// It uses "create" driver (see SetCreateDriver)
+func (sdr *Seedr) TryCreate(traitName string) (TraitInstance, error) {
+	return sdr.CreateCustomContext(context.Background(), traitName, nil)
+}
+
+// TryBuildCustom builds trait with additional changes.
+// It uses "build" driver (see SetBuildDriver)
+func (sdr *Seedr) TryBuildCustom(traitName string, override Trait) (TraitInstance, error) {
+	return sdr.BuildCustomContext(context.Background(), traitName, override)
+}
+
+// TryBuildCustomBatch builds n trait instances with additional changes.
+// It uses "build" driver (see SetBuildDriver)
+func (sdr *Seedr) TryBuildCustomBatch(traitName string, n int, override Trait) (*TraitInstances, error) {
+	return sdr.BuildCustomBatchContext(context.Background(), traitName, n, override)
+}
+
+// TryBuildBatch builds n trait instances.
+// It uses "build" driver (see SetBuildDriver)
+func (sdr *Seedr) TryBuildBatch(traitName string, n int) (*TraitInstances, error) {
+	return sdr.BuildCustomBatchContext(context.Background(), traitName, n, nil)
+}
+
+// TryBuild builds trait.
+// It uses "build" driver (see SetBuildDriver)
+func (sdr *Seedr) TryBuild(traitName string) (TraitInstance, error) {
+	return sdr.BuildCustomContext(context.Background(), traitName, nil)
+}
+
 // CreateCustom overrides values of trait definition and creates resulting trait.
 // It uses "create" driver (see SetCreateDriver)
 func (sdr *Seedr) CreateCustom(traitName string, override Trait) TraitInstance {
-	t := sdr.getPublicTrait(traitName)
-	ins := t.create(override, 1)
-	return ins.Index(0)
+	ti, err := sdr.TryCreateCustom(traitName, override)
+	panicOnError(err)
+	return ti
 }
 
 // CreateCustomBatch overrides values of trait definition
 // and creates n instances of resulting trait.
 // It uses "create" driver (see SetCreateDriver)
 func (sdr *Seedr) CreateCustomBatch(traitName string, n int, override Trait) *TraitInstances {
-	t := sdr.getPublicTrait(traitName)
-	return t.create(override, n)
+	ins, err := sdr.TryCreateCustomBatch(traitName, n, override)
+	panicOnError(err)
+	return ins
 }
 
 // CreateBatch creates n instances of trait
 // It uses "create" driver (see SetCreateDriver)
 func (sdr *Seedr) CreateBatch(traitName string, n int) *TraitInstances {
-	t := sdr.getPublicTrait(traitName)
-	return t.create(nil, n)
+	ins, err := sdr.TryCreateBatch(traitName, n)
+	panicOnError(err)
+	return ins
 }
 
 // Create creates an instance of trait
 // It uses "create" driver (see SetCreateDriver)
 func (sdr *Seedr) Create(traitName string) TraitInstance {
-	t := sdr.getPublicTrait(traitName)
-	ins := t.create(nil, 1)
-	return ins.Index(0)
+	ti, err := sdr.TryCreate(traitName)
+	panicOnError(err)
+	return ti
 }
 
 // BuildCustom builds trait with additional changes.
 // It uses "build" driver (see SetBuildDriver)
 func (sdr *Seedr) BuildCustom(traitName string, override Trait) TraitInstance {
-	t := sdr.getPublicTrait(traitName)
-	ins := t.build(override, 1)
-	return ins.Index(0)
+	ti, err := sdr.TryBuildCustom(traitName, override)
+	panicOnError(err)
+	return ti
 }
 
 // BuildCustomBatch builds n trait instances with additional changes.
 // It uses "build" driver (see SetBuildDriver)
 func (sdr *Seedr) BuildCustomBatch(traitName string, n int, override Trait) *TraitInstances {
-	t := sdr.getPublicTrait(traitName)
-	return t.build(override, n)
+	ins, err := sdr.TryBuildCustomBatch(traitName, n, override)
+	panicOnError(err)
+	return ins
 }
 
 // BuildBatch builds n trait instances.
 // It uses "build" driver (see SetBuildDriver)
 func (sdr *Seedr) BuildBatch(traitName string, n int) *TraitInstances {
-	t := sdr.getPublicTrait(traitName)
-	return t.build(nil, n)
+	ins, err := sdr.TryBuildBatch(traitName, n)
+	panicOnError(err)
+	return ins
 }
 
 // Build builds trait.
 // It uses "build" driver (see SetBuildDriver)
 func (sdr *Seedr) Build(traitName string) TraitInstance {
-	t := sdr.getPublicTrait(traitName)
-	ins := t.build(nil, 1)
-	return ins.Index(0)
+	ti, err := sdr.TryBuild(traitName)
+	panicOnError(err)
+	return ti
+}
+
+// BuildStubbedCustomContext builds trait with additional changes.
+// Auto() primary keys get fake per-entity incrementing values
+// and foreign keys of related traits are set accordingly.
+// It uses stub.StubDriver.
+func (sdr *Seedr) BuildStubbedCustomContext(ctx context.Context, traitName string, override Trait) (TraitInstance, error) {
+	ins, err := sdr.BuildStubbedCustomBatchContext(ctx, traitName, 1, override)
+	if err != nil {
+		return TraitInstance{}, err
+	}
+	return ins.Index(0), nil
+}
+
+// BuildStubbedCustomBatchContext builds n trait instances with additional changes.
+// See BuildStubbedCustomContext.
+func (sdr *Seedr) BuildStubbedCustomBatchContext(ctx context.Context, traitName string, n int, override Trait) (*TraitInstances, error) {
+	o := newCreateOp(ctx, sdr)
+	ins, err := o.create(StrategyStub, traitName, n, override)
+	if err = o.finish(err); err != nil {
+		return nil, err
+	}
+	return ins, nil
+}
+
+// BuildStubbedBatchContext builds n trait instances.
+// See BuildStubbedCustomContext.
+func (sdr *Seedr) BuildStubbedBatchContext(ctx context.Context, traitName string, n int) (*TraitInstances, error) {
+	return sdr.BuildStubbedCustomBatchContext(ctx, traitName, n, nil)
+}
+
+// BuildStubbedContext builds trait.
+// See BuildStubbedCustomContext.
+func (sdr *Seedr) BuildStubbedContext(ctx context.Context, traitName string) (TraitInstance, error) {
+	return sdr.BuildStubbedCustomContext(ctx, traitName, nil)
+}
+
+// TryBuildStubbedCustom builds trait with additional changes.
+// See BuildStubbedCustomContext.
+func (sdr *Seedr) TryBuildStubbedCustom(traitName string, override Trait) (TraitInstance, error) {
+	return sdr.BuildStubbedCustomContext(context.Background(), traitName, override)
+}
+
+// TryBuildStubbedCustomBatch builds n trait instances with additional changes.
+// See BuildStubbedCustomContext.
+func (sdr *Seedr) TryBuildStubbedCustomBatch(traitName string, n int, override Trait) (*TraitInstances, error) {
+	return sdr.BuildStubbedCustomBatchContext(context.Background(), traitName, n, override)
+}
+
+// TryBuildStubbedBatch builds n trait instances.
+// See BuildStubbedCustomContext.
+func (sdr *Seedr) TryBuildStubbedBatch(traitName string, n int) (*TraitInstances, error) {
+	return sdr.BuildStubbedCustomBatchContext(context.Background(), traitName, n, nil)
+}
+
+// TryBuildStubbed builds trait.
+// See BuildStubbedCustomContext.
+func (sdr *Seedr) TryBuildStubbed(traitName string) (TraitInstance, error) {
+	return sdr.BuildStubbedCustomContext(context.Background(), traitName, nil)
+}
+
+// BuildStubbedCustom builds trait with additional changes.
+// See BuildStubbedCustomContext.
+func (sdr *Seedr) BuildStubbedCustom(traitName string, override Trait) TraitInstance {
+	ti, err := sdr.TryBuildStubbedCustom(traitName, override)
+	panicOnError(err)
+	return ti
+}
+
+// BuildStubbedCustomBatch builds n trait instances with additional changes.
+// See BuildStubbedCustomContext.
+func (sdr *Seedr) BuildStubbedCustomBatch(traitName string, n int, override Trait) *TraitInstances {
+	ins, err := sdr.TryBuildStubbedCustomBatch(traitName, n, override)
+	panicOnError(err)
+	return ins
+}
+
+// BuildStubbedBatch builds n trait instances.
+// See BuildStubbedCustomContext.
+func (sdr *Seedr) BuildStubbedBatch(traitName string, n int) *TraitInstances {
+	ins, err := sdr.TryBuildStubbedBatch(traitName, n)
+	panicOnError(err)
+	return ins
+}
+
+// BuildStubbed builds trait.
+// See BuildStubbedCustomContext.
+func (sdr *Seedr) BuildStubbed(traitName string) TraitInstance {
+	ti, err := sdr.TryBuildStubbed(traitName)
+	panicOnError(err)
+	return ti
 }
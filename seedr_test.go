@@ -1,10 +1,19 @@
 package seedr
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/josephbuchma/seedr/driver"
+	"github.com/josephbuchma/seedr/driver/noop"
 )
 
 func TestTrait_buildPublics(t *testing.T) {
@@ -226,26 +235,25 @@ func Test_resolveDependentFields(t *testing.T) {
 			},
 		}
 
-		rt := pub.next(1, nil)
+		rt, err := pub.next(1, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
 		if rt.data[0]["first_name"].(string) != "Jon-1" {
 			t.Fatalf("Invalid first_name value")
 		}
 		if d, ok := rt.dependent["full_name"]; !ok || !stringSice(d.fields).contains("first_name") {
 			t.Fatalf("Invalid dependent: %#v", rt.dependent)
 		}
-		resolveDependentFields(rt.data[0], rt.dependent)
+		if err := resolveDependentFields(rt.data[0], rt.dependent); err != nil {
+			t.Fatal(err)
+		}
 		if fn, ok := rt.data[0]["full_name"].(string); !ok || fn != "Jon-1 Snow-1" {
 			t.Fatalf("invalid full_name, expected %s, got %s", "Jon-1 Snow-1", fn)
 		}
 	})
 
-	t.Run("Must panic on circular dependency", func(t *testing.T) {
-		defer func() {
-			r := recover().(string)
-			if !strings.HasPrefix(r, "Circular field dependency:") {
-				t.Fatalf("no panic")
-			}
-		}()
+	t.Run("Must fail on circular dependency", func(t *testing.T) {
 		pub := &publicTrait{
 			trait: Trait{
 				"a": DependsOn("b").Generate(func(t Trait) interface{} { return nil }),
@@ -255,7 +263,1294 @@ func Test_resolveDependentFields(t *testing.T) {
 			},
 		}
 
-		rt := pub.next(1, nil)
-		resolveDependentFields(rt.data[0], rt.dependent)
+		rt, _ := pub.next(1, nil)
+		err := resolveDependentFields(rt.data[0], rt.dependent)
+		if err == nil || !strings.HasPrefix(err.Error(), "Circular field dependency:") {
+			t.Fatalf("Expected circular dependency error, got %v", err)
+		}
+	})
+}
+
+type failingDriver struct {
+	err error
+}
+
+func (d failingDriver) Create(driver.Payload) ([]map[string]interface{}, error) {
+	return nil, d.err
+}
+
+func TestTryAPI(t *testing.T) {
+	drvErr := errors.New("connection refused")
+	sdr := New("test_try",
+		SetCreateDriver(failingDriver{drvErr}),
+		SetFieldMapper(SnakeFieldMapper()),
+	).Add("users", Factory{
+		FactoryConfig: FactoryConfig{PrimaryKey: "id"},
+		Traits: Traits{
+			"User": {
+				"id":   Auto(),
+				"name": "Jon",
+			},
+		},
+	})
+
+	t.Run("Trait not found", func(t *testing.T) {
+		_, err := sdr.TryBuild("Nope")
+		if !errors.Is(err, ErrTraitNotFound) {
+			t.Fatalf("Expected ErrTraitNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Driver error", func(t *testing.T) {
+		_, err := sdr.TryCreateBatch("User", 2)
+		var de *DriverError
+		if !errors.As(err, &de) {
+			t.Fatalf("Expected DriverError, got %v", err)
+		}
+		if de.Entity != "users" || len(de.Payload.Data) != 2 || !errors.Is(err, drvErr) {
+			t.Errorf("Invalid DriverError: %#v", de)
+		}
+	})
+
+	t.Run("Scan error", func(t *testing.T) {
+		var u struct {
+			Name int
+		}
+		ti, err := sdr.TryBuild("User")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = ti.TryScan(&u)
+		var se *ScanError
+		if !errors.As(err, &se) {
+			t.Fatalf("Expected ScanError, got %v", err)
+		}
+		if se.Field != "Name" || se.Key != "name" {
+			t.Errorf("Invalid ScanError: %#v", se)
+		}
+	})
+
+	t.Run("Invalid definition", func(t *testing.T) {
+		_, err := sdr.TryCreateCustom("User", Trait{
+			"a": DependsOn("b").Generate(func(Trait) interface{} { return 1 }),
+			"b": DependsOn("a").Generate(func(Trait) interface{} { return 2 }),
+		})
+		if err == nil || !strings.Contains(err.Error(), "Circular field dependency") {
+			t.Errorf("Expected circular dependency error, got %v", err)
+		}
+		_, err = sdr.TryBuildCustom("User", Trait{"name": WithStrategy(StrategyCreate, SequenceInt())})
+		if err == nil || !strings.Contains(err.Error(), "is not a relation") {
+			t.Errorf("Expected WithStrategy error, got %v", err)
+		}
+		ti, err := sdr.TryBuild("User")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ti.TryScanRelated("nope", &[]map[string]interface{}{}); err == nil {
+			t.Error("Expected error for unknown relation")
+		}
+	})
+
+	t.Run("Panicking API", func(t *testing.T) {
+		defer func() {
+			if err, ok := recover().(error); !ok || !errors.Is(err, ErrTraitNotFound) {
+				t.Fatalf("Expected panic with ErrTraitNotFound, got %v", err)
+			}
+		}()
+		sdr.Create("Nope")
+	})
+}
+
+// recordingDriver assigns sequential primary keys and records created entities.
+type recordingDriver struct {
+	entities []string
+	ids      map[string]int
+	onCreate func(p driver.Payload)
+}
+
+func (d *recordingDriver) Create(p driver.Payload) ([]map[string]interface{}, error) {
+	if d.ids == nil {
+		d.ids = make(map[string]int)
+	}
+	d.entities = append(d.entities, p.Entity)
+	if d.onCreate != nil {
+		d.onCreate(p)
+	}
+	ret := make([]map[string]interface{}, len(p.Data))
+	for i, rec := range p.Data {
+		ret[i] = make(map[string]interface{})
+		for k, v := range rec {
+			ret[i][k] = v
+		}
+		if _, ok := rec[p.PrimaryKey]; !ok && p.PrimaryKey != "" {
+			d.ids[p.Entity]++
+			ret[i][p.PrimaryKey] = d.ids[p.Entity]
+		}
+	}
+	return ret, nil
+}
+
+func testRelationsSeedr(config ...ConfigFunc) *Seedr {
+	return New("test_relations", append([]ConfigFunc{SetFieldMapper(SnakeFieldMapper())}, config...)...).
+		Add("users", Factory{
+			FactoryConfig{Entity: "users", PrimaryKey: "id", UniqueBy: []string{"name"}},
+			Relations{
+				"articles": HasMany("articles", "author_id"),
+			},
+			Traits{
+				"User": {
+					"id":   Auto(),
+					"name": SequenceString("User-%d"),
+				},
+				"UserWithArticles": {
+					Include:    "User",
+					"articles": CreateRelatedBatch("Article", 2),
+				},
+			},
+		}).
+		Add("articles", Factory{
+			FactoryConfig{Entity: "articles", PrimaryKey: "id"},
+			Relations{
+				"author": BelongsTo("users", "author_id"),
+			},
+			Traits{
+				"Article": {
+					"id":        Auto(),
+					"author_id": nil,
+					"title":     SequenceString("Title-%d"),
+				},
+				"ArticleWithAuthor": {
+					Include:  "Article",
+					"author": CreateRelated("User"),
+				},
+			},
+		})
+}
+
+func TestCreateContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	drv := &recordingDriver{onCreate: func(driver.Payload) { cancel() }}
+	sdr := testRelationsSeedr(SetCreateDriver(drv))
+
+	_, err := sdr.CreateContext(ctx, "UserWithArticles")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if !reflect.DeepEqual(drv.entities, []string{"users"}) {
+		t.Errorf("Expected creation to stop after users, got %v", drv.entities)
+	}
+}
+
+// txDriver is a transactional recordingDriver that fails on creation of failOn entity.
+type txDriver struct {
+	recordingDriver
+	failOn string
+	log    []string
+}
+
+func (d *txDriver) Create(p driver.Payload) ([]map[string]interface{}, error) {
+	if p.Entity == d.failOn {
+		return nil, errors.New("failed")
+	}
+	return d.recordingDriver.Create(p)
+}
+
+func (d *txDriver) Begin(context.Context) (driver.Tx, error) {
+	d.log = append(d.log, "BEGIN")
+	return &txDriverTx{d}, nil
+}
+
+type txDriverTx struct {
+	*txDriver
+}
+
+func (tx *txDriverTx) Create(p driver.Payload) ([]map[string]interface{}, error) {
+	tx.log = append(tx.log, p.Entity)
+	return tx.txDriver.Create(p)
+}
+
+func (tx *txDriverTx) Commit() error {
+	tx.log = append(tx.log, "COMMIT")
+	return nil
+}
+
+func (tx *txDriverTx) Rollback() error {
+	tx.log = append(tx.log, "ROLLBACK")
+	return nil
+}
+
+func TestUnitOfWork(t *testing.T) {
+	t.Run("Commit", func(t *testing.T) {
+		drv := &txDriver{}
+		sdr := testRelationsSeedr(SetCreateDriver(drv), SetUnitOfWork(true))
+		sdr.Create("UserWithArticles")
+		expected := []string{"BEGIN", "users", "articles", "COMMIT"}
+		if !reflect.DeepEqual(drv.log, expected) {
+			t.Errorf("Expected %v, got %v", expected, drv.log)
+		}
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		drv := &txDriver{failOn: "articles"}
+		sdr := testRelationsSeedr(SetCreateDriver(drv), SetUnitOfWork(true))
+		_, err := sdr.TryCreate("UserWithArticles")
+		var de *DriverError
+		if !errors.As(err, &de) || de.Entity != "articles" {
+			t.Fatalf("Expected DriverError, got %v", err)
+		}
+		expected := []string{"BEGIN", "users", "articles", "ROLLBACK"}
+		if !reflect.DeepEqual(drv.log, expected) {
+			t.Errorf("Expected %v, got %v", expected, drv.log)
+		}
+	})
+
+	t.Run("Driver without transactions", func(t *testing.T) {
+		sdr := testRelationsSeedr(SetUnitOfWork(true))
+		if _, err := sdr.TryCreate("User"); err == nil {
+			t.Fatal("Expected error")
+		}
+	})
+}
+
+func TestBegin(t *testing.T) {
+	drv := &txDriver{}
+	sdr := testRelationsSeedr(SetCreateDriver(drv))
+	tsdr, tx, err := sdr.Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tsdr.Create("User")
+	sdr.Create("User")
+	tx.Rollback()
+	expected := []string{"BEGIN", "users", "ROLLBACK"}
+	if !reflect.DeepEqual(drv.log, expected) {
+		t.Errorf("Expected %v, got %v", expected, drv.log)
+	}
+
+	tsdr.Add("other", Factory{Traits: Traits{"Other": {"id": 1}}})
+	if _, err := sdr.TryBuild("Other"); !errors.Is(err, ErrTraitNotFound) {
+		t.Errorf("Expected factory added to derived Seedr to be invisible in original, got %v", err)
+	}
+
+	if _, _, err := testRelationsSeedr().Begin(context.Background()); err == nil {
+		t.Error("Expected error for driver without transactions")
+	}
+}
+
+// deletingDriver is a recordingDriver that logs deleted records.
+type deletingDriver struct {
+	recordingDriver
+	deleted []string
+}
+
+func (d *deletingDriver) Delete(_ context.Context, p driver.Payload) error {
+	for _, rec := range p.Data {
+		d.deleted = append(d.deleted, fmt.Sprintf("%s:%v", p.Entity, rec[p.PrimaryKey]))
+	}
+	return nil
+}
+
+func TestSessionCleanup(t *testing.T) {
+	drv := &deletingDriver{}
+	s := testRelationsSeedr(SetCreateDriver(drv)).Session()
+	s.Create("User")
+	s.Create("UserWithArticles")
+	s.Build("User")
+
+	if err := s.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"articles:1", "articles:2", "users:2", "users:1"}
+	if !reflect.DeepEqual(drv.deleted, expected) {
+		t.Errorf("Expected %v, got %v", expected, drv.deleted)
+	}
+
+	drv.deleted = nil
+	if err := s.Cleanup(); err != nil || len(drv.deleted) != 0 {
+		t.Errorf("Expected nothing to cleanup, got %v (%v)", drv.deleted, err)
+	}
+
+	if err := testRelationsSeedr(SetCreateDriver(&recordingDriver{})).Session().Cleanup(); err == nil {
+		t.Error("Expected error for driver without deletion")
+	}
+}
+
+// upsertingDriver is a deletingDriver that finds existing records by "name"
+// and records conflicts of upserts.
+type upsertingDriver struct {
+	deletingDriver
+	byName    map[interface{}]map[string]interface{}
+	conflicts []driver.Conflict
+}
+
+func (d *upsertingDriver) Upsert(_ context.Context, p driver.Payload) ([]map[string]interface{}, []bool, error) {
+	if d.byName == nil {
+		d.byName = make(map[interface{}]map[string]interface{})
+	}
+	d.conflicts = append(d.conflicts, *p.Conflict)
+	ret := make([]map[string]interface{}, len(p.Data))
+	found := make([]bool, len(p.Data))
+	for i, rec := range p.Data {
+		stored, ok := d.byName[rec["name"]]
+		if !ok {
+			res, _ := d.Create(driver.Payload{Entity: p.Entity, PrimaryKey: p.PrimaryKey, Data: p.Data[i : i+1]})
+			stored = res[0]
+			d.byName[rec["name"]] = stored
+		}
+		found[i] = ok
+		for _, f := range p.Conflict.Update {
+			stored[f] = rec[f]
+		}
+		ret[i] = make(map[string]interface{})
+		for k, v := range stored {
+			ret[i][k] = v
+		}
+		if p.Conflict.Strategy == driver.ConflictIgnore {
+			for _, f := range p.InsertFields {
+				ret[i][f] = rec[f]
+			}
+		}
+	}
+	return ret, found, nil
+}
+
+func TestFindOrCreate(t *testing.T) {
+	type user struct {
+		ID    int
+		Name  string
+		Email string
+	}
+	drv := &upsertingDriver{}
+	s := testRelationsSeedr(SetCreateDriver(drv)).Session()
+
+	var u1, u2, u3 user
+	s.FindOrCreateCustom("User", Trait{"name": "Jon", "email": "jon@example.com"}).Scan(&u1)
+	s.FindOrCreateCustom("User", Trait{"name": "Jon", "email": "changed@example.com"}).Scan(&u2)
+	s.UpsertCustom("User", Trait{"name": "Jon", "email": "upserted@example.com"}).Scan(&u3)
+	if u1.ID != 1 || u2 != u1 {
+		t.Errorf("Expected existing user to be found, got %#v and %#v", u1, u2)
+	}
+	if u3.ID != 1 || u3.Email != "upserted@example.com" {
+		t.Errorf("Expected existing user to be updated, got %#v", u3)
+	}
+
+	var art struct{ AuthorID int }
+	s.FindOrCreateCustom("ArticleWithAuthor", Trait{
+		"author": CreateRelatedCustom("User", Trait{"name": "Jon"}),
+	}).Scan(&art)
+	if art.AuthorID != 1 {
+		t.Errorf("Expected article of existing user, got author %d", art.AuthorID)
+	}
+
+	fetch := driver.Conflict{Strategy: driver.ConflictFetch, Key: []string{"name"}}
+	expected := []driver.Conflict{
+		fetch, fetch,
+		{Strategy: driver.ConflictUpdate, Key: []string{"name"}, Update: []string{"email"}},
+		fetch,
+	}
+	if !reflect.DeepEqual(expected, drv.conflicts) {
+		t.Errorf("Expected conflicts %#v, got %#v", expected, drv.conflicts)
+	}
+
+	// user was created by first FindOrCreate only, so it's deleted once
+	if err := s.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(drv.deleted, []string{"articles:1", "users:1"}) {
+		t.Errorf("Expected article and user to be deleted, got %v", drv.deleted)
+	}
+
+	// records that existed before session are not deleted
+	drv.deleted = nil
+	s = testRelationsSeedr(SetCreateDriver(drv)).Session()
+	s.FindOrCreateCustomBatch("User", 2, Trait{"name": Loop([]string{"Jon", "Arya"})})
+	if err := s.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(drv.deleted, []string{"users:2"}) {
+		t.Errorf("Expected only new user to be deleted, got %v", drv.deleted)
+	}
+
+	if _, err := testRelationsSeedr(SetCreateDriver(&recordingDriver{})).TryFindOrCreate("User"); err == nil {
+		t.Error("Expected error for driver without Upsert")
+	}
+}
+
+func TestFindOrCreateRelated(t *testing.T) {
+	drv := &upsertingDriver{}
+	sdr := testRelationsSeedr(SetCreateDriver(drv))
+
+	sdr.FindOrCreateBatch("UserWithArticles", 2)
+	ins := sdr.FindOrCreateCustomBatch("UserWithArticles", 2, Trait{"name": SequenceString("User-%d", 2)})
+	upserted := sdr.UpsertCustom("UserWithArticles", Trait{"name": "User-1"})
+
+	// articles are created only for new users (2 per user)
+	if drv.ids["articles"] != 6 {
+		t.Errorf("Expected 6 articles to be created, got %d", drv.ids["articles"])
+	}
+	if l := upserted.Related("articles").Len(); l != 0 {
+		t.Errorf("Expected no articles of existing user, got %d", l)
+	}
+	for i, expected := range []int{0, 2} {
+		if l := ins.Index(i).Related("articles").Len(); l != expected {
+			t.Errorf("Expected %d articles of user %d, got %d", expected, i, l)
+		}
+	}
+	var u struct{ ID int }
+	var arts []struct{ AuthorID int }
+	ins.Index(1).Scan(&u).ScanRelated("articles", &arts)
+	if u.ID != 3 || arts[0].AuthorID != 3 || arts[1].AuthorID != 3 {
+		t.Errorf("Expected articles of new user %d, got %v", u.ID, arts)
+	}
+}
+
+func TestConflictOptions(t *testing.T) {
+	type user struct {
+		ID    int
+		Name  string
+		Email string
+		Role  string
+	}
+	drv := &upsertingDriver{}
+	sdr := testRelationsSeedr(SetCreateDriver(drv))
+
+	var u1, u2, u3 user
+	sdr.FindOrCreateCustom("User", Trait{"name": "Jon", "email": "jon@example.com", "role": "user"}).Scan(&u1)
+	sdr.FindOrCreateCustom("User", Trait{"name": "Jon", "email": "given@example.com"}, KeepGiven()).Scan(&u2)
+	if u2.ID != u1.ID || u2.Email != "given@example.com" {
+		t.Errorf("Expected existing user with given values, got %#v", u2)
+	}
+	if stored := drv.byName["Jon"]["email"]; stored != "jon@example.com" {
+		t.Errorf("Expected existing user to be unchanged, got email %v", stored)
+	}
+	if s := drv.conflicts[1].Strategy; s != driver.ConflictIgnore {
+		t.Errorf("Expected ConflictIgnore, got %v", s)
+	}
+
+	sdr.UpsertCustom("User", Trait{"name": "Jon", "email": "upserted@example.com", "role": "admin"},
+		UpdateFields("role", "unknown")).Scan(&u3)
+	if u3.ID != u1.ID || u3.Email != "jon@example.com" || u3.Role != "admin" {
+		t.Errorf("Expected only role of existing user to be updated, got %#v", u3)
+	}
+	if update := drv.conflicts[2].Update; !reflect.DeepEqual(update, []string{"role"}) {
+		t.Errorf("Expected update of role only, got %v", update)
+	}
+
+	if _, err := sdr.TryUpsert("User", KeepGiven()); err == nil {
+		t.Error("Expected error for KeepGiven of Upsert")
+	}
+	if _, err := sdr.TryFindOrCreate("User", UpdateFields("email")); err == nil {
+		t.Error("Expected error for UpdateFields of FindOrCreate")
+	}
+}
+
+// storeDriver is a recordingDriver that keeps created records, so they can be found later.
+type storeDriver struct {
+	recordingDriver
+	store map[string]map[interface{}]map[string]interface{}
+}
+
+func (d *storeDriver) Create(p driver.Payload) ([]map[string]interface{}, error) {
+	ret, err := d.recordingDriver.Create(p)
+	if d.store == nil {
+		d.store = make(map[string]map[interface{}]map[string]interface{})
+	}
+	if d.store[p.Entity] == nil {
+		d.store[p.Entity] = make(map[interface{}]map[string]interface{})
+	}
+	for _, rec := range ret {
+		cp := make(map[string]interface{})
+		for k, v := range rec {
+			cp[k] = v
+		}
+		d.store[p.Entity][rec[p.PrimaryKey]] = cp
+	}
+	return ret, err
+}
+
+func (d *storeDriver) Find(_ context.Context, p driver.Payload) ([]map[string]interface{}, error) {
+	ret := make([]map[string]interface{}, len(p.Data))
+	for i, rec := range p.Data {
+		stored, ok := d.store[p.Entity][rec[p.PrimaryKey]]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		ret[i] = make(map[string]interface{})
+		for _, f := range p.ReturnFields {
+			ret[i][f] = stored[f]
+		}
+	}
+	return ret, nil
+}
+
+func TestReload(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+	type article struct {
+		ID    int
+		Title string
+	}
+	drv := &storeDriver{}
+	sdr := testRelationsSeedr(SetCreateDriver(drv))
+
+	ti := sdr.Create("UserWithArticles")
+	drv.store["users"][1]["name"] = "Reloaded"
+	drv.store["articles"][2]["title"] = "Reloaded"
+
+	var u user
+	var arts []article
+	ti.Reload().Scan(&u).ScanRelated("articles", &arts)
+	if u.Name != "Reloaded" || arts[0].Title != "Title-1" || arts[1].Title != "Reloaded" {
+		t.Errorf("Expected records to be reloaded, got %#v, %#v", u, arts)
+	}
+
+	drv.store["users"][1]["name"] = "Reloaded again"
+	var users []user
+	sdr.CreateBatch("User", 2).Reload().Scan(&users)
+	ti.Related("articles").Index(0).Reload()
+	ti.Scan(&u)
+	if u.Name != "Reloaded" {
+		t.Errorf("Expected parent not to be reloaded by child, got %q", u.Name)
+	}
+
+	delete(drv.store["users"], 1)
+	if err := ti.TryReload(); err == nil {
+		t.Error("Expected error for deleted record")
+	}
+	if err := testRelationsSeedr(SetCreateDriver(&recordingDriver{})).Create("User").TryReload(); err == nil {
+		t.Error("Expected error for driver without Find")
+	}
+
+	if err := sdr.Build("User").TryReload(); !errors.Is(err, ErrNotCreated) {
+		t.Errorf("Expected ErrNotCreated for built instance, got %v", err)
+	}
+	if err := sdr.BuildStubbedBatch("User", 2).TryReload(); !errors.Is(err, ErrNotCreated) {
+		t.Errorf("Expected ErrNotCreated for stubbed instances, got %v", err)
+	}
+
+	// built children of created user are skipped
+	ti = sdr.CreateCustom("UserWithArticles", Trait{
+		"articles": WithStrategy(StrategyBuild, CreateRelatedBatch("Article", 2)),
 	})
+	drv.store["users"][ti.insts.data[0]["id"]]["name"] = "Reloaded"
+	ti.Reload().Scan(&u)
+	if u.Name != "Reloaded" {
+		t.Errorf("Expected created user to be reloaded, got %q", u.Name)
+	}
+}
+
+func (d *storeDriver) Update(_ context.Context, p driver.Payload) error {
+	for _, rec := range p.Data {
+		stored, ok := d.store[p.Entity][rec[p.PrimaryKey]]
+		if !ok {
+			return errors.New("not found")
+		}
+		for _, f := range p.InsertFields {
+			stored[f] = rec[f]
+		}
+	}
+	return nil
+}
+
+func TestUpdate(t *testing.T) {
+	type user struct {
+		ID    int
+		Name  string
+		Email string
+	}
+	drv := &storeDriver{}
+	sdr := testRelationsSeedr(SetCreateDriver(drv))
+
+	var u user
+	sdr.Create("User").Update(Trait{"name": "Updated"}).Scan(&u)
+	if u.Name != "Updated" || drv.store["users"][1]["name"] != "Updated" {
+		t.Errorf("Expected user to be updated, got %#v, stored %v", u, drv.store["users"][1])
+	}
+
+	var users []user
+	sdr.CreateBatch("User", 2).UpdateAll(Trait{
+		"name": SequenceString("Renamed-%d"),
+		"email": DependsOn("id", "name").Generate(func(t Trait) interface{} {
+			return fmt.Sprintf("%v-%v@example.com", t["id"], t["name"])
+		}),
+	}).Scan(&users)
+	expected := []user{
+		{ID: 2, Name: "Renamed-1", Email: "2-Renamed-1@example.com"},
+		{ID: 3, Name: "Renamed-2", Email: "3-Renamed-2@example.com"},
+	}
+	if !reflect.DeepEqual(expected, users) {
+		t.Errorf("Expected %#v, got %#v", expected, users)
+	}
+	if drv.store["users"][3]["email"] != "3-Renamed-2@example.com" {
+		t.Errorf("Expected update to be stored, got %v", drv.store["users"][3])
+	}
+
+	type rename struct {
+		Name  string
+		Email string
+	}
+	typed := TypedFactory[rename]{
+		Base: func(r *rename, seq int) {
+			r.Name = fmt.Sprintf("Typed-%d", seq)
+			r.Email = fmt.Sprintf("typed-%d@example.com", seq)
+		},
+		Traits: map[string]func(*rename){"Rename": nil},
+	}.Factory(sdr).Traits["Rename"]
+	users = nil
+	sdr.CreateBatch("User", 2).UpdateAll(typed).Scan(&users)
+	for _, u := range users {
+		var seq int
+		fmt.Sscanf(u.Name, "Typed-%d", &seq)
+		if seq == 0 || u.Email != fmt.Sprintf("typed-%d@example.com", seq) {
+			t.Errorf("Expected fields of the same typed instance, got %#v", u)
+		}
+	}
+
+	if err := sdr.Create("User").TryUpdate(Trait{"id": 10}); err == nil {
+		t.Error("Expected error on primary key update")
+	}
+	if err := sdr.Create("User").TryUpdate(Trait{Include: "User"}); err == nil {
+		t.Error("Expected error on Include in update")
+	}
+	if err := testRelationsSeedr(SetCreateDriver(&recordingDriver{})).Create("User").TryUpdate(Trait{"name": "x"}); err == nil {
+		t.Error("Expected error for driver without Update")
+	}
+
+	if err := sdr.Build("User").TryUpdate(Trait{"name": "x"}); !errors.Is(err, ErrNotCreated) {
+		t.Errorf("Expected ErrNotCreated for built instance, got %v", err)
+	}
+	if err := sdr.BuildStubbedBatch("User", 2).TryUpdateAll(Trait{"name": "x"}); !errors.Is(err, ErrNotCreated) {
+		t.Errorf("Expected ErrNotCreated for stubbed instances, got %v", err)
+	}
+}
+
+func TestBuildStrategy(t *testing.T) {
+	cdrv, bdrv := &recordingDriver{}, &recordingDriver{}
+	sdr := testRelationsSeedr(SetCreateDriver(cdrv), SetBuildDriver(bdrv))
+	check := func(name string, created, built []string) {
+		t.Helper()
+		if !reflect.DeepEqual(cdrv.entities, created) || !reflect.DeepEqual(bdrv.entities, built) {
+			t.Errorf("%s: expected created %v and built %v, got %v and %v",
+				name, created, built, cdrv.entities, bdrv.entities)
+		}
+		cdrv.entities, bdrv.entities = nil, nil
+	}
+
+	sdr.Build("ArticleWithAuthor")
+	check("parent", nil, []string{"users", "articles"})
+
+	sdr.Build("UserWithArticles").CreateRelated("articles", "Article")
+	check("children", nil, []string{"users", "articles", "articles"})
+
+	sdr.BuildCustom("ArticleWithAuthor", Trait{
+		"author": WithStrategy(StrategyCreate, CreateRelated("User")),
+	})
+	check("persisted parent", []string{"users"}, []string{"articles"})
+
+	sdr.CreateCustom("UserWithArticles", Trait{
+		"articles": WithStrategy(StrategyBuild, CreateRelatedBatch("Article", 2)),
+	})
+	check("built children", []string{"users"}, []string{"articles"})
+}
+
+func TestBuildStubbed(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+	type article struct {
+		ID       int
+		AuthorID int
+	}
+	cdrv := &recordingDriver{}
+	sdr := testRelationsSeedr(SetCreateDriver(cdrv))
+
+	var arts []article
+	var authors []user
+	sdr.BuildStubbedBatch("ArticleWithAuthor", 2).Scan(&arts).Index(1).ScanRelated("author", &authors)
+	expected := []article{{ID: 1, AuthorID: 1}, {ID: 2, AuthorID: 2}}
+	if !reflect.DeepEqual(expected, arts) {
+		t.Errorf("Expected %#v, got %#v", expected, arts)
+	}
+	if len(authors) != 1 || authors[0].ID != 2 {
+		t.Errorf("Expected author with ID 2, got %#v", authors)
+	}
+
+	var u user
+	sdr.BuildStubbed("UserWithArticles").Scan(&u).ScanRelated("articles", &arts)
+	expected = []article{{ID: 3, AuthorID: 3}, {ID: 4, AuthorID: 3}}
+	if u.ID != 3 || !reflect.DeepEqual(expected, arts) {
+		t.Errorf("Expected user 3 with articles %#v, got %#v, %#v", expected, u, arts)
+	}
+
+	sdr.BuildStubbedCustom("Article", Trait{"id": 100})
+	if ti := sdr.BuildStubbed("Article"); ti.insts.data[0]["id"] != int64(5) {
+		t.Errorf("Expected explicit id to be kept and counter not advanced, got %v", ti.insts.data[0]["id"])
+	}
+	if len(cdrv.entities) != 0 {
+		t.Errorf("Expected nothing to be created, got %v", cdrv.entities)
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	drv := &recordingDriver{}
+	sdr := testRelationsSeedr(SetCreateDriver(drv), SetBuildDriver(drv)).Add("profiles", Factory{
+		FactoryConfig{Entity: "profiles", PrimaryKey: "id"},
+		nil,
+		Traits{
+			"Profile": {
+				"id":         Auto(),
+				"first_name": "Jon",
+				"last_name":  SequenceString("Snow-%d"),
+				"full_name": DependsOn("first_name", "last_name").Generate(func(t Trait) interface{} {
+					return fmt.Sprintf("%s %s", t["first_name"], t["last_name"])
+				}),
+			},
+		},
+	})
+
+	attrs := sdr.AttributesCustomBatch("Profile", 2, Trait{"first_name": "Arya"})
+	expected := []Trait{
+		{"first_name": "Arya", "last_name": "Snow-1", "full_name": "Arya Snow-1"},
+		{"first_name": "Arya", "last_name": "Snow-2", "full_name": "Arya Snow-2"},
+	}
+	if !reflect.DeepEqual(expected, attrs) {
+		t.Errorf("Expected %#v, got %#v", expected, attrs)
+	}
+
+	if a := sdr.Attributes("ArticleWithAuthor"); !reflect.DeepEqual(a, Trait{"title": "Title-1"}) {
+		t.Errorf("Expected relations to be omitted, got %#v", a)
+	}
+	if len(drv.entities) != 0 {
+		t.Errorf("Expected driver not to be called, got %v", drv.entities)
+	}
+
+	type base struct {
+		FirstName string `json:"firstName"`
+	}
+	type profile struct {
+		base
+		LastName string `json:"lastName,omitempty"`
+		FullName string `json:"-"`
+	}
+	js, err := sdr.ToJSON(attrs[0], &profile{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(js); s != `{"firstName":"Arya","full_name":"Arya Snow-1","lastName":"Snow-1"}` {
+		t.Errorf("Unexpected JSON %s", s)
+	}
+}
+
+func TestGenericAPI(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+	type article struct {
+		ID       int
+		AuthorID int
+		Title    string
+	}
+	sdr := testRelationsSeedr(SetCreateDriver(&recordingDriver{}))
+
+	u, ti := Create[user](sdr, "UserWithArticles")
+	if u.ID != 1 || u.Name != "User-1" {
+		t.Errorf("Unexpected user %#v", u)
+	}
+	arts := Related[article](ti, "articles")
+	expected := []article{{1, 1, "Title-1"}, {2, 1, "Title-2"}}
+	if !reflect.DeepEqual(expected, arts) {
+		t.Errorf("Expected %#v, got %#v", expected, arts)
+	}
+
+	users, _ := CreateBatch[user](sdr, "User", 2)
+	if len(users) != 2 || users[1].ID != 3 {
+		t.Errorf("Unexpected users %#v", users)
+	}
+	built, _ := BuildBatch[user](sdr, "User", 1)
+	if len(built) != 1 || built[0].ID != 0 {
+		t.Errorf("Unexpected built users %#v", built)
+	}
+
+	if _, _, err := TryCreate[user](sdr, "NoSuchTrait"); !errors.Is(err, ErrTraitNotFound) {
+		t.Errorf("Expected ErrTraitNotFound, got %v", err)
+	}
+	if _, err := TryRelated[article](ti, "comments"); err == nil {
+		t.Error("Expected error for unknown relation")
+	}
+	if _, _, err := TryBuild[int](sdr, "User"); err == nil {
+		t.Error("Expected error for non-struct type")
+	}
+}
+
+func TestTypedFactory(t *testing.T) {
+	type user struct {
+		ID     int
+		Name   string
+		Admin  bool
+		Nick   *string
+		Ignore []string `seedr:"-"`
+	}
+	type article struct {
+		ID       int
+		AuthorID int
+		Title    string
+	}
+	drv := &recordingDriver{}
+	sdr := New("typed", SetFieldMapper(SnakeFieldMapper()), SetCreateDriver(drv))
+	sdr.Add("users", TypedFactory[user]{
+		FactoryConfig: FactoryConfig{Entity: "users", PrimaryKey: "id"},
+		Relations: Relations{
+			"articles": HasMany("articles", "author_id"),
+		},
+		Base: func(u *user, seq int) {
+			u.Name = fmt.Sprintf("User-%d", seq)
+		},
+		Traits: map[string]func(*user){
+			"User":  nil,
+			"Admin": func(u *user) { u.Admin = true },
+		},
+		Extend: Traits{
+			"UserWithArticles": {
+				Include:    "User",
+				"articles": CreateRelatedBatch("Article", 2),
+			},
+		},
+	}.Factory(sdr))
+	sdr.Add("articles", TypedFactory[article]{
+		FactoryConfig: FactoryConfig{Entity: "articles", PrimaryKey: "id"},
+		Relations: Relations{
+			"author": BelongsTo("users", "author_id"),
+		},
+		Base: func(a *article, seq int) {
+			a.Title = fmt.Sprintf("Title-%d", seq)
+		},
+		Traits: map[string]func(*article){
+			"Article": nil,
+		},
+		Extend: Traits{
+			"ArticleWithAuthor": {
+				Include:  "Article",
+				"author": CreateRelated("Admin"),
+			},
+		},
+	}.Factory(sdr))
+
+	users, _ := CreateBatch[user](sdr, "User", 2)
+	expected := []user{{ID: 1, Name: "User-1"}, {ID: 2, Name: "User-2"}}
+	if !reflect.DeepEqual(expected, users) {
+		t.Errorf("Expected %#v, got %#v", expected, users)
+	}
+
+	a, ti := Create[article](sdr, "ArticleWithAuthor")
+	authors := Related[user](ti, "author")
+	if a.AuthorID != 3 || authors[0].ID != 3 || !authors[0].Admin || authors[0].Name != "User-1" {
+		t.Errorf("Unexpected article %#v with author %#v", a, authors)
+	}
+
+	u, ti := Create[user](sdr, "UserWithArticles")
+	arts := Related[article](ti, "articles")
+	if u.Name != "User-3" || len(arts) != 2 || arts[1].AuthorID != u.ID {
+		t.Errorf("Unexpected user %#v with articles %#v", u, arts)
+	}
+
+	ins := sdr.CreateCustomBatch("User", 2, Trait{"name": "Custom", "nick": "nick"})
+	var custom []user
+	ins.Scan(&custom)
+	if custom[0].Name != "Custom" || custom[1].Name != "Custom" || ins.data[1]["nick"] != "nick" {
+		t.Errorf("Expected overrides to be applied, got %#v", ins.data)
+	}
+	if ins.data[0]["admin"] != false || ins.data[0]["id"] == nil {
+		t.Errorf("Unexpected record %#v", ins.data[0])
+	}
+}
+
+func TestTypedFactoryConcurrent(t *testing.T) {
+	type user struct {
+		Name  string
+		Email string
+	}
+	sdr := New("typed", SetFieldMapper(SnakeFieldMapper()))
+	sdr.Add("users", TypedFactory[user]{
+		Base: func(u *user, seq int) {
+			u.Name = fmt.Sprintf("User-%d", seq)
+			u.Email = fmt.Sprintf("user-%d@example.com", seq)
+		},
+		Traits: map[string]func(*user){"User": nil},
+	}.Factory(sdr))
+
+	var wg sync.WaitGroup
+	results := make([][]Trait, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = sdr.AttributesBatch("User", 50)
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[interface{}]bool)
+	for _, attrs := range results {
+		for _, a := range attrs {
+			var seq int
+			fmt.Sscanf(a["name"].(string), "User-%d", &seq)
+			if a["email"] != fmt.Sprintf("user-%d@example.com", seq) || seen[seq] {
+				t.Fatalf("Fields of different instances are mixed: %v", a)
+			}
+			seen[seq] = true
+		}
+	}
+
+	a := sdr.AttributesCustom("User", Trait{"name": "Custom"})
+	if a["email"] != "user-401@example.com" {
+		t.Errorf("Expected single instance per record, got %v", a)
+	}
+}
+
+func TestCreateFrom(t *testing.T) {
+	type user struct {
+		ID     int
+		Name   string
+		Active bool
+		Note   *string
+	}
+	drv := &recordingDriver{}
+	sdr := New("from", SetFieldMapper(SnakeFieldMapper()), SetCreateDriver(drv)).Add("users", Factory{
+		FactoryConfig{Entity: "users", PrimaryKey: "id"},
+		nil,
+		Traits{
+			"User": {
+				"id":     Auto(),
+				"name":   "Jon",
+				"active": true,
+			},
+		},
+	})
+
+	var u user
+	sdr.CreateFrom("User", &user{Name: "Mike"}).Scan(&u)
+	if u != (user{ID: 1, Name: "Mike", Active: true}) {
+		t.Errorf("Unexpected user %#v", u)
+	}
+	sdr.CreateFrom("User", user{}, Fields("Active")).Scan(&u)
+	if u != (user{ID: 2, Name: "Jon", Active: false}) {
+		t.Errorf("Expected zero field to be forced, got %#v", u)
+	}
+	note := "note"
+	ti := sdr.BuildFrom("User", user{Note: &note})
+	if ti.insts.data[0]["note"] != "note" {
+		t.Errorf("Expected pointer to be dereferenced, got %#v", ti.insts.data[0])
+	}
+
+	if _, err := sdr.TryCreateFrom("User", user{}, Fields("Missing")); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+	if _, err := sdr.TryCreateFrom("User", 1); err == nil {
+		t.Error("Expected error for non-struct value")
+	}
+}
+
+func TestScanDeep(t *testing.T) {
+	type user struct {
+		ID       int
+		Name     string
+		Articles []*struct {
+			ID       int
+			AuthorID int
+			Title    string
+		}
+	}
+	type article struct {
+		ID       int
+		AuthorID int
+		Title    string
+		Writer   user `seedr:"rel=author"`
+	}
+	sdr := testRelationsSeedr(SetCreateDriver(&recordingDriver{}))
+
+	var u user
+	sdr.Create("UserWithArticles").ScanDeep(&u)
+	if u.ID != 1 || len(u.Articles) != 2 {
+		t.Fatalf("Unexpected user %#v", u)
+	}
+	for _, a := range u.Articles {
+		if a.AuthorID != u.ID || a.Title == "" {
+			t.Errorf("Unexpected article %#v", a)
+		}
+	}
+
+	var arts []article
+	sdr.CreateBatch("ArticleWithAuthor", 2).ScanDeep(&arts)
+	for _, a := range arts {
+		if a.Writer.ID == 0 || a.Writer.ID != a.AuthorID || a.Writer.Name == "" {
+			t.Errorf("Unexpected article %#v", a)
+		}
+	}
+
+	var missing struct {
+		ID    int
+		Other []user `seedr:"rel=other"`
+	}
+	if err := sdr.Create("User").TryScanDeep(&missing); err == nil {
+		t.Error("Expected error for unknown relation")
+	}
+
+	// relation fields matched by name are not reported in strict mode
+	strict := sdr.With(SetStrictScan(true))
+	if err := strict.Create("UserWithArticles").TryScanDeep(&u); err != nil {
+		t.Errorf("Unexpected error in strict mode: %v", err)
+	}
+	if err := strict.CreateBatch("ArticleWithAuthor", 2).TryScanDeep(&arts); err != nil {
+		t.Errorf("Unexpected error in strict mode: %v", err)
+	}
+	if err := strict.Create("User").TryScan(&u); err != nil {
+		t.Errorf("Unexpected error in strict mode: %v", err)
+	}
+}
+
+func scanTestSeedr() *Seedr {
+	return New("scan", SetFieldMapper(SnakeFieldMapper()), SetCreateDriver(noop.NoopDriver{})).Add("users", Factory{
+		FactoryConfig{Entity: "users", PrimaryKey: "id"},
+		nil,
+		Traits{
+			"User": {
+				"id":         SequenceInt(),
+				"name":       SequenceString("User-%d"),
+				"created_at": time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	})
+}
+
+func BenchmarkScanBatch(b *testing.B) {
+	type user struct {
+		ID        int
+		Name      string
+		Email     string
+		Active    bool
+		CreatedAt time.Time
+	}
+	sdr := New("bench", SetFieldMapper(SnakeFieldMapper()), SetCreateDriver(noop.NoopDriver{})).Add("users", Factory{
+		FactoryConfig{Entity: "users", PrimaryKey: "id"},
+		nil,
+		Traits{
+			"User": {
+				"id":         SequenceInt(),
+				"name":       SequenceString("User-%d"),
+				"email":      SequenceString("user-%d@example.com"),
+				"active":     true,
+				"created_at": time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	})
+	ins := sdr.CreateBatch("User", 1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		var users []user
+		ins.Scan(&users)
+	}
+}
+
+type scanTimestamps struct {
+	CreatedAt time.Time
+}
+
+func TestScanPointerSlice(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+	var users []*user
+	scanTestSeedr().CreateBatch("User", 2).Scan(&users)
+	if len(users) != 2 || *users[0] != (user{1, "User-1"}) || *users[1] != (user{2, "User-2"}) {
+		t.Errorf("Unexpected users %v", users)
+	}
+}
+
+func TestScanEmbedded(t *testing.T) {
+	type user struct {
+		scanTimestamps
+		ID   int
+		Name string
+	}
+	var u user
+	scanTestSeedr().Create("User").Scan(&u)
+	if u.ID != 1 || u.Name != "User-1" || u.CreatedAt.Year() != 2020 {
+		t.Errorf("Unexpected user %#v", u)
+	}
+
+	type shadowed struct {
+		scanTimestamps
+		CreatedAt string
+	}
+	var s shadowed
+	scanTestSeedr().Create("User").Scan(&s)
+	if s.CreatedAt == "" || !s.scanTimestamps.CreatedAt.IsZero() {
+		t.Errorf("Expected outer field to take precedence, got %#v", s)
+	}
+}
+
+func TestScanEmbeddedPointer(t *testing.T) {
+	type Timestamps struct {
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}
+	type user struct {
+		*Timestamps
+		ID int
+	}
+	var u user
+	scanTestSeedr().Create("User").Scan(&u)
+	if u.Timestamps == nil || u.CreatedAt.Year() != 2020 || u.ID != 1 {
+		t.Errorf("Expected embedded pointer to be allocated, got %#v", u)
+	}
+
+	updated := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	ts := &Timestamps{UpdatedAt: updated}
+	u = user{Timestamps: ts}
+	scanTestSeedr().Create("User").Scan(&u)
+	if u.Timestamps != ts || u.CreatedAt.Year() != 2020 || u.UpdatedAt != updated {
+		t.Errorf("Expected existing embedded struct to be filled, got %#v", u.Timestamps)
+	}
+
+	var unexported struct {
+		*scanTimestamps
+		ID int
+	}
+	if err := scanTestSeedr().Create("User").TryScan(&unexported); err == nil {
+		t.Error("Expected error for nil pointer to unexported struct")
+	}
+}
+
+func TestScanMap(t *testing.T) {
+	sdr := scanTestSeedr()
+	var m map[string]interface{}
+	sdr.Create("User").Scan(&m)
+	if m["id"] != 1 || m["name"] != "User-1" {
+		t.Errorf("Unexpected map %v", m)
+	}
+
+	var ms []Trait
+	sdr.CreateBatch("User", 2).Scan(&ms)
+	if len(ms) != 2 || ms[1]["name"] != "User-3" {
+		t.Errorf("Unexpected maps %v", ms)
+	}
+}
+
+func TestScanSkipTag(t *testing.T) {
+	type user struct {
+		ID    int
+		Name  string      `seedr:"-"`
+		Cache map[int]int `seedr:"-"`
+	}
+	var u user
+	scanTestSeedr().Create("User").Scan(&u)
+	if u.ID != 1 || u.Name != "" || u.Cache != nil {
+		t.Errorf("Unexpected user %#v", u)
+	}
+}
+
+func TestStrictScan(t *testing.T) {
+	type user struct {
+		ID    int
+		Name  string
+		Email string
+	}
+	sdr := scanTestSeedr()
+	var u user
+	if err := sdr.Create("User").TryScan(&u); err != nil {
+		t.Fatalf("Unexpected error in non-strict mode: %v", err)
+	}
+
+	strict := sdr.With(SetStrictScan(true))
+	err := strict.Create("User").TryScan(&u)
+	var se *StrictScanError
+	if !errors.As(err, &se) {
+		t.Fatalf("Expected *StrictScanError, got %v", err)
+	}
+	if !reflect.DeepEqual(se.UnmappedFields, []string{"Email (email)"}) {
+		t.Errorf("Unexpected unmapped fields %v", se.UnmappedFields)
+	}
+	if !reflect.DeepEqual(se.UnusedKeys, []string{"created_at"}) {
+		t.Errorf("Unexpected unused keys %v", se.UnusedKeys)
+	}
+
+	var ok struct {
+		scanTimestamps
+		ID   int
+		Name string
+		Skip string `seedr:"-"`
+	}
+	if err := strict.Create("User").TryScan(&ok); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+type testStatus string
+
+type testMeta struct {
+	Color string `json:"color"`
+}
+
+func TestValueEncoder(t *testing.T) {
+	factory := Factory{
+		FactoryConfig{Entity: "items", PrimaryKey: "id"},
+		nil,
+		Traits{
+			"Item": {
+				"id":     Auto(),
+				"status": testStatus("active"),
+				"note":   sql.NullString{String: "note", Valid: true},
+				"raw":    json.RawMessage(`{"a":1}`),
+				"tags":   []string{"a", "b"},
+				"meta":   testMeta{Color: "red"},
+				"extra":  map[string]interface{}{"n": 1},
+			},
+		},
+	}
+	if _, err := New("enc", SetCreateDriver(&recordingDriver{})).Add("items", factory).TryCreate("Item"); err == nil {
+		t.Fatal("Expected error for values without encoder")
+	}
+
+	var payload driver.Payload
+	drv := &recordingDriver{onCreate: func(p driver.Payload) { payload = p }}
+	sdr := New("enc",
+		SetFieldMapper(SnakeFieldMapper()),
+		SetCreateDriver(drv),
+		SetValueEncoder(map[string]interface{}{}, JSONEncoder()),
+		SetValueEncoder(reflect.Slice, JSONEncoder()),
+		SetValueEncoder(reflect.Struct, JSONEncoder()),
+	).Add("items", factory)
+
+	ti := sdr.Create("Item")
+	rec := payload.Data[0]
+	if rec["tags"] != `["a","b"]` || rec["meta"] != `{"color":"red"}` || rec["extra"] != `{"n":1}` {
+		t.Errorf("Unexpected encoded values %v", rec)
+	}
+	if _, ok := rec["note"].(sql.NullString); !ok {
+		t.Errorf("Expected driver.Valuer to be passed as is, got %T", rec["note"])
+	}
+
+	var item struct {
+		ID     int
+		Status testStatus
+		Note   sql.NullString
+		Raw    json.RawMessage
+		Tags   []string
+		Meta   *testMeta
+		Extra  map[string]interface{}
+	}
+	ti.Scan(&item)
+	if item.Status != "active" || item.Note.String != "note" || string(item.Raw) != `{"a":1}` {
+		t.Errorf("Unexpected item %#v", item)
+	}
+	if !reflect.DeepEqual(item.Tags, []string{"a", "b"}) || item.Meta == nil || item.Meta.Color != "red" || item.Extra["n"] != 1.0 {
+		t.Errorf("Unexpected decoded values %#v", item)
+	}
 }
@@ -0,0 +1,43 @@
+// Package seedrtest provides per-test isolation helpers for Seedr.
+package seedrtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/josephbuchma/seedr"
+)
+
+// Begin starts transaction on "create" driver of sdr (it must implement driver.Transactor)
+// and returns Seedr bound to it. Transaction is rolled back when t and all its subtests complete.
+//
+// Begin may be called again with returned Seedr (e.g. in subtests);
+// SQL drivers emulate such nested transactions using SAVEPOINT.
+// Returned Seedr must not be shared by parallel tests.
+func Begin(t testing.TB, sdr *seedr.Seedr) *seedr.Seedr {
+	t.Helper()
+	tsdr, tx, err := sdr.Begin(context.Background())
+	if err != nil {
+		t.Fatalf("seedrtest: failed to begin transaction: %s", err)
+	}
+	t.Cleanup(func() {
+		if err := tx.Rollback(); err != nil {
+			t.Errorf("seedrtest: failed to rollback transaction: %s", err)
+		}
+	})
+	return tsdr
+}
+
+// Session returns new Session of sdr. Records created by it are deleted
+// when t and all its subtests complete (see seedr.Session.Cleanup).
+// Use it when records can't be created in transaction.
+func Session(t testing.TB, sdr *seedr.Seedr) *seedr.Session {
+	t.Helper()
+	s := sdr.Session()
+	t.Cleanup(func() {
+		if err := s.Cleanup(); err != nil {
+			t.Errorf("seedrtest: failed to cleanup session: %s", err)
+		}
+	})
+	return s
+}
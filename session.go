@@ -0,0 +1,66 @@
+package seedr
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/josephbuchma/seedr/driver"
+)
+
+// Session is a Seedr that keeps track of all records it creates,
+// so they can be deleted by Cleanup.
+// It is useful when records can't be created in transaction
+// (e.g. when application under test uses its own db connections).
+type Session struct {
+	*Seedr
+
+	mu sync.Mutex
+	// created contains payloads with results of every
+	// driver's Create call, in order of creation.
+	created []driver.Payload
+}
+
+// Session returns new Session with all factories of this Seedr.
+func (sdr *Seedr) Session() *Session {
+	s := &Session{}
+	s.Seedr = sdr.With(func(cp *Seedr) {
+		cp.session = s
+	})
+	return s
+}
+
+func (s *Session) track(created ...driver.Payload) {
+	s.mu.Lock()
+	s.created = append(s.created, created...)
+	s.mu.Unlock()
+}
+
+// Cleanup is same as CleanupContext with context.Background().
+func (s *Session) Cleanup() error {
+	return s.CleanupContext(context.Background())
+}
+
+// CleanupContext deletes all records created in this session in reverse order
+// of creation, so join records of M2M relations and children are deleted before parents.
+// "Create" driver must implement driver.Deleter.
+// Records of factories without PrimaryKey are deleted by values of all their
+// fields, so identical records that were not created in this session are deleted too.
+// If deletion fails, records that were not deleted yet are kept for next Cleanup.
+func (s *Session) CleanupContext(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.createDriver.(driver.Deleter)
+	if !ok {
+		return fmt.Errorf("cleanup: driver %T does not support deletion", s.createDriver)
+	}
+	for i := len(s.created) - 1; i >= 0; i-- {
+		p := s.created[i]
+		if err := d.Delete(ctx, p); err != nil {
+			s.created = s.created[:i+1]
+			return &DriverError{Entity: p.Entity, Payload: p, Err: err}
+		}
+	}
+	s.created = nil
+	return nil
+}
@@ -0,0 +1,47 @@
+package seedr
+
+import "fmt"
+
+// Strategy defines how trait instances are created.
+// Related traits inherit strategy of trait they belong to,
+// e.g. Build* builds all related traits with "build" driver.
+type Strategy int
+
+const (
+	// StrategyInherit uses strategy of trait that relation belongs to.
+	StrategyInherit Strategy = iota
+	// StrategyCreate stores instances using "create" driver (see SetCreateDriver).
+	StrategyCreate
+	// StrategyBuild builds instances using "build" driver (see SetBuildDriver).
+	StrategyBuild
+	// StrategyStub builds instances with fake primary keys (see BuildStubbed).
+	StrategyStub
+)
+
+// inherit returns s, or parent if s is StrategyInherit.
+func (s Strategy) inherit(parent Strategy) Strategy {
+	if s == StrategyInherit {
+		return parent
+	}
+	return s
+}
+
+// WithStrategy sets strategy of relation created by given generator
+// (CreateRelated and friends), regardless of strategy of trait it belongs to.
+// Example (article is built, but its author is stored in database):
+//
+//	sdr.BuildCustom("Article", seedr.Trait{
+//	  "author": seedr.WithStrategy(seedr.StrategyCreate, seedr.CreateRelated("User")),
+//	})
+func WithStrategy(s Strategy, g Generator) Generator {
+	return Func(func() interface{} {
+		v := g.Next()
+		rel, ok := v.(*relationField)
+		if !ok {
+			return generatorError{fmt.Errorf("WithStrategy: %T is not a relation", v)}
+		}
+		cp := *rel
+		cp.strategy = s
+		return &cp
+	})
+}
@@ -0,0 +1,194 @@
+package seedr
+
+import (
+	"reflect"
+	"sync"
+)
+
+// TypedFactory is a struct-based alternative to Factory.
+// It's compiled to Factory by Factory method, so typed and map-based
+// factories can coexist and relate to each other.
+// Exported fields of T are mapped to Trait fields using MapFieldFunc of Seedr.
+// Fields tagged with `seedr:"-"` and fields of unsupported types (see Trait) are skipped.
+// Zero value of PrimaryKey field is treated as Auto(),
+// and zero value of BelongsTo foreign key field as nil.
+// Example:
+//
+//	sdr.Add("users", seedr.TypedFactory[User]{
+//	  FactoryConfig: seedr.FactoryConfig{Entity: "users", PrimaryKey: "id"},
+//	  Base: func(u *User, seq int) {
+//	    u.Name = fmt.Sprintf("User %d", seq)
+//	  },
+//	  Traits: map[string]func(*User){
+//	    "User":  nil,
+//	    "Admin": func(u *User) { u.Admin = true },
+//	  },
+//	}.Factory(sdr))
+type TypedFactory[T any] struct {
+	FactoryConfig
+	Relations
+	// Base initializes every instance, seq starts from 1 (for every public trait).
+	Base func(v *T, seq int)
+	// Traits modify instances initialized by Base. Names follow same rules
+	// as names of Traits of Factory. Func may be nil.
+	Traits map[string]func(v *T)
+	// Extend contains map-based traits, they may include typed traits
+	// (e.g. to add relations).
+	Extend Traits
+}
+
+type typedField struct {
+	index []int
+	// name of struct field
+	name string
+	key  string
+	// zeroAuto and zeroNil define what is returned instead of zero value of field
+	zeroAuto, zeroNil bool
+}
+
+// typedState creates instances of T for typed trait.
+type typedState[T any] struct {
+	base  func(*T, int)
+	trait func(*T)
+
+	mu  sync.Mutex
+	seq int
+}
+
+// instance initializes new instance of T using base and trait funcs.
+func (s *typedState[T]) instance() *T {
+	s.mu.Lock()
+	s.seq++
+	seq := s.seq
+	s.mu.Unlock()
+	v := new(T)
+	if s.base != nil {
+		s.base(v, seq)
+	}
+	if s.trait != nil {
+		s.trait(v)
+	}
+	return v
+}
+
+// typedFieldGen is a generator of typed trait field. All fields of single record
+// are taken from the same instance of T, which is kept in recordScope.
+type typedFieldGen[T any] struct {
+	state *typedState[T]
+	field typedField
+}
+
+// Next returns field of new instance, it's used only if field
+// is evaluated outside of record.
+func (g typedFieldGen[T]) Next() interface{} {
+	return g.value(g.state.instance())
+}
+
+func (g typedFieldGen[T]) nextInRecord(rec recordScope) interface{} {
+	v, ok := rec[g.state].(*T)
+	if !ok {
+		v = g.state.instance()
+		rec[g.state] = v
+	}
+	return g.value(v)
+}
+
+func (g typedFieldGen[T]) value(v *T) interface{} {
+	f := g.field
+	fv := reflect.ValueOf(v).Elem().FieldByIndex(f.index)
+	if fv.IsZero() {
+		switch {
+		case f.zeroAuto:
+			return auto{}
+		case f.zeroNil:
+			return nil
+		}
+	}
+	return fieldValue(fv)
+}
+
+// fieldValue returns value of struct field, pointers are dereferenced.
+func fieldValue(fv reflect.Value) interface{} {
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+	return fv.Interface()
+}
+
+// typedFields returns all fields of struct type t that can be used in Trait.
+func (sdr *Seedr) typedFields(t reflect.Type, index []int) ([]typedField, error) {
+	var ret []typedField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Tag.Get("seedr") == "-" {
+			continue
+		}
+		idx := append(index[:len(index):len(index)], i)
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		_, err := getFieldValue(reflect.Zero(ft).Interface(), sdr.encoders)
+		supported := err == nil
+		if f.Anonymous && !supported && f.Type.Kind() == reflect.Struct {
+			fields, err := sdr.typedFields(f.Type, idx)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, fields...)
+			continue
+		}
+		if f.PkgPath != "" || !supported {
+			continue
+		}
+		key, err := sdr.extractFieldName(f)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, typedField{index: idx, name: f.Name, key: key})
+	}
+	return ret, nil
+}
+
+// Factory compiles TypedFactory to Factory using MapFieldFunc of sdr,
+// so it must be called after SetFieldMapper. It panics on invalid definition.
+func (tf TypedFactory[T]) Factory(sdr *Seedr) Factory {
+	fields, err := sdr.typedFields(reflect.TypeOf((*T)(nil)).Elem(), nil)
+	panicOnError(err)
+
+	parentFKs := make(map[string]bool)
+	for name, rel := range tf.Relations {
+		if rel.kind != relationParent {
+			continue
+		}
+		if rel.lfield == "" {
+			parentFKs[name] = true
+		} else {
+			parentFKs[rel.lfield] = true
+		}
+	}
+	for i, f := range fields {
+		fields[i].zeroAuto = tf.PrimaryKey != "" && f.key == tf.PrimaryKey
+		fields[i].zeroNil = parentFKs[f.key]
+	}
+
+	traits := make(Traits, len(tf.Traits)+len(tf.Extend))
+	for name, fn := range tf.Traits {
+		st := &typedState[T]{base: tf.Base, trait: fn}
+		tr := make(Trait, len(fields))
+		for _, f := range fields {
+			tr[f.key] = typedFieldGen[T]{st, f}
+		}
+		traits[name] = tr
+	}
+	for name, tr := range tf.Extend {
+		if _, ok := traits[name]; ok {
+			panicf("Trait %q is defined in both Traits and Extend", name)
+		}
+		traits[name] = tr
+	}
+	return Factory{tf.FactoryConfig, tf.Relations, traits}
+}
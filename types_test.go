@@ -2,6 +2,7 @@ package seedr
 
 import (
 	"reflect"
+	"regexp"
 	"testing"
 )
 
@@ -43,3 +44,54 @@ func TestPickRandom(t *testing.T) {
 		t.Errorf("PickRandom failed")
 	}
 }
+
+func TestUUID(t *testing.T) {
+	canonical := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	u := UUID().Next().(BinaryUUID)
+	if !canonical.MatchString(u.String()) {
+		t.Errorf("Invalid UUID %s", u)
+	}
+	if s := UUIDString().Next().(string); !canonical.MatchString(s) {
+		t.Errorf("Invalid UUID string %s", s)
+	}
+
+	v, _ := u.Value()
+	for _, src := range []interface{}{v, u.String(), []byte(u.String())} {
+		var scanned BinaryUUID
+		if err := scanned.Scan(src); err != nil || scanned != u {
+			t.Errorf("Expected %s to be scanned from %#v, got %s (%v)", u, src, scanned, err)
+		}
+	}
+	var scanned BinaryUUID
+	if err := scanned.Scan("not a uuid"); err == nil {
+		t.Error("Expected error for invalid UUID")
+	}
+}
+
+func TestULID(t *testing.T) {
+	valid := regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+	g := ULID()
+	prev := ""
+	for i := 0; i < 100; i++ {
+		id := g.Next().(string)
+		if !valid.MatchString(id) {
+			t.Fatalf("Invalid ULID %s", id)
+		}
+		if id <= prev {
+			t.Fatalf("Expected ULIDs to increase, got %s after %s", id, prev)
+		}
+		prev = id
+	}
+
+	var id [16]byte
+	id[15] = 1
+	if s := encodeULID(id); s != "00000000000000000000000001" {
+		t.Errorf("Unexpected encoding %s", s)
+	}
+	for i := range id {
+		id[i] = 0xff
+	}
+	if s := encodeULID(id); s != "7ZZZZZZZZZZZZZZZZZZZZZZZZZ" {
+		t.Errorf("Unexpected encoding %s", s)
+	}
+}
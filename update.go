@@ -0,0 +1,134 @@
+package seedr
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/josephbuchma/seedr/driver"
+)
+
+// nextUpdate evaluates fields of update trait for every record of data
+// (using recordEval, same as publicTrait.next), so DependsOn fields may use both
+// updated and current values of record. Include, relations and Auto()
+// are not supported in update trait.
+// It returns list of updated fields and new records, that contain
+// primary key and updated fields only.
+func nextUpdate(upd Trait, pk string, data []map[string]interface{}, encs valueEncoders) ([]string, []map[string]interface{}, error) {
+	var fields []string
+	for k := range upd {
+		if k == Include {
+			return nil, nil, fmt.Errorf("Include is not supported in update, fields must be given explicitly")
+		}
+		if k == pk {
+			return nil, nil, fmt.Errorf("primary key %q can't be updated", k)
+		}
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+
+	ret := make([]map[string]interface{}, len(data))
+	for i, rec := range data {
+		merged := make(map[string]interface{}, len(rec)+len(fields))
+		for k, v := range rec {
+			merged[k] = v
+		}
+		dependent := make(map[string]dependentField)
+		eval := newRecordEval(merged, dependent, encs)
+		for _, k := range fields {
+			fv, err := eval.field(k, upd[k])
+			if err != nil {
+				return nil, nil, err
+			}
+			switch fv.(type) {
+			case *relationField, auto:
+				return nil, nil, fmt.Errorf("field %q: relations and Auto are not supported in update", k)
+			}
+		}
+		if len(dependent) > 0 {
+			if err := resolveDependentFields(merged, dependent); err != nil {
+				return nil, nil, err
+			}
+		}
+		ret[i] = map[string]interface{}{pk: rec[pk]}
+		for _, k := range fields {
+			ret[i][k] = merged[k]
+		}
+	}
+	return fields, ret, nil
+}
+
+// update updates given records (subset of ti.data) using "create" driver
+// and applies new values to them.
+func (ti *TraitInstances) update(ctx context.Context, upd Trait, data []map[string]interface{}) error {
+	if err := ti.checkCreated("update"); err != nil {
+		return err
+	}
+	u, ok := ti.sdr.createDriver.(driver.Updater)
+	if !ok {
+		return fmt.Errorf("update: driver %T does not support updating records", ti.sdr.createDriver)
+	}
+	pk, err := ti.trait.factory.FactoryConfig.pk()
+	if err != nil {
+		return err
+	}
+	fields, recs, err := nextUpdate(upd, pk, data, ti.sdr.encoders)
+	if err != nil || len(fields) == 0 || len(recs) == 0 {
+		return err
+	}
+	p := driver.Payload{
+		Entity:       ti.trait.factory.FactoryConfig.Entity,
+		PrimaryKey:   pk,
+		InsertFields: fields,
+		Data:         recs,
+	}
+	if err := u.Update(ctx, p); err != nil {
+		return &DriverError{Entity: p.Entity, Payload: p, Err: err}
+	}
+	for i, rec := range recs {
+		for _, f := range fields {
+			data[i][f] = rec[f]
+		}
+	}
+	return nil
+}
+
+// UpdateContext stores given changes of TraitInstance.
+// Generators and DependsOn fields of upd are evaluated same way as on creation.
+// Include, relations and Auto() fields are not supported.
+// "Create" driver must implement driver.Updater.
+// Built and stubbed instances can't be updated (see ErrNotCreated).
+func (ti TraitInstance) UpdateContext(ctx context.Context, upd Trait) error {
+	return ti.insts.update(ctx, upd, ti.insts.data[ti.i:ti.i+1])
+}
+
+// TryUpdate is same as UpdateContext with context.Background().
+func (ti TraitInstance) TryUpdate(upd Trait) error {
+	return ti.UpdateContext(context.Background(), upd)
+}
+
+// Update is same as TryUpdate, but it panics on error.
+func (ti TraitInstance) Update(upd Trait) TraitInstance {
+	panicOnError(ti.TryUpdate(upd))
+	return ti
+}
+
+// UpdateAllContext stores given changes of all trait instances.
+// Generators and DependsOn fields of upd are evaluated for every instance
+// (see UpdateContext).
+// "Create" driver must implement driver.Updater.
+// Built and stubbed instances can't be updated (see ErrNotCreated).
+func (ti *TraitInstances) UpdateAllContext(ctx context.Context, upd Trait) error {
+	return ti.update(ctx, upd, ti.data)
+}
+
+// TryUpdateAll is same as UpdateAllContext with context.Background().
+func (ti *TraitInstances) TryUpdateAll(upd Trait) error {
+	return ti.UpdateAllContext(context.Background(), upd)
+}
+
+// UpdateAll is same as TryUpdateAll, but it panics on error.
+func (ti *TraitInstances) UpdateAll(upd Trait) *TraitInstances {
+	panicOnError(ti.TryUpdateAll(upd))
+	return ti
+}
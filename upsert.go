@@ -0,0 +1,197 @@
+package seedr
+
+import (
+	"context"
+	"errors"
+
+	"github.com/josephbuchma/seedr/driver"
+)
+
+// ConflictOption configures FindOrCreate* and Upsert* calls.
+type ConflictOption func(*conflictConfig)
+
+type conflictConfig struct {
+	strategy driver.ConflictStrategy
+	// keepGiven is set by KeepGiven
+	keepGiven bool
+	// update contains fields given by UpdateFields (if onlyUpdate is set)
+	update     []string
+	onlyUpdate bool
+}
+
+// KeepGiven makes FindOrCreate* return given values of records that already exist
+// instead of stored ones (see driver.ConflictIgnore): existing records are left unchanged,
+// and only fields that were not given (e.g. auto-generated primary key) are taken from them.
+// It can't be used by Upsert*.
+func KeepGiven() ConflictOption {
+	return func(c *conflictConfig) {
+		c.keepGiven = true
+	}
+}
+
+// UpdateFields makes Upsert* update only given fields of existing records,
+// instead of all fields except UniqueBy fields and PrimaryKey.
+// It applies to all upserted records (including related ones), fields that are
+// not set by trait of record are skipped. It can't be used by FindOrCreate*.
+func UpdateFields(fields ...string) ConflictOption {
+	return func(c *conflictConfig) {
+		c.update = append(c.update, fields...)
+		c.onlyUpdate = true
+	}
+}
+
+// createConflict creates n instances of trait using "create" driver,
+// traits of factories with UniqueBy are created using given conflict strategy.
+func (sdr *Seedr) createConflict(ctx context.Context, s driver.ConflictStrategy, traitName string, n int, override Trait, opts []ConflictOption) (*TraitInstances, error) {
+	c := conflictConfig{strategy: s}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	switch {
+	case c.keepGiven && s != driver.ConflictFetch:
+		return nil, errors.New("KeepGiven can be used only by FindOrCreate*")
+	case c.onlyUpdate && s != driver.ConflictUpdate:
+		return nil, errors.New("UpdateFields can be used only by Upsert*")
+	case c.keepGiven:
+		c.strategy = driver.ConflictIgnore
+	}
+	o := newCreateOp(ctx, sdr)
+	o.conflict = c
+	ins, err := o.create(StrategyCreate, traitName, n, override)
+	if err = o.finish(err); err != nil {
+		return nil, err
+	}
+	return ins, nil
+}
+
+// FindOrCreateCustomBatchContext overrides values of trait definition
+// and finds or creates n instances of resulting trait (including related traits).
+// Records of factories with UniqueBy are created only if there is no record
+// with same values of UniqueBy fields, otherwise existing record is returned unchanged
+// (with given values instead of stored ones if KeepGiven option is given).
+// Child and many to many related records of existing records are not created.
+// Existing records are not tracked by Session, so Cleanup doesn't delete them.
+// "Create" driver must implement driver.Upserter (see SetCreateDriver).
+func (sdr *Seedr) FindOrCreateCustomBatchContext(ctx context.Context, traitName string, n int, override Trait, opts ...ConflictOption) (*TraitInstances, error) {
+	return sdr.createConflict(ctx, driver.ConflictFetch, traitName, n, override, opts)
+}
+
+// TryFindOrCreateCustomBatch overrides values of trait definition
+// and finds or creates n instances of resulting trait (see FindOrCreateCustomBatchContext).
+func (sdr *Seedr) TryFindOrCreateCustomBatch(traitName string, n int, override Trait, opts ...ConflictOption) (*TraitInstances, error) {
+	return sdr.FindOrCreateCustomBatchContext(context.Background(), traitName, n, override, opts...)
+}
+
+// TryFindOrCreateCustom overrides values of trait definition
+// and finds or creates resulting trait (see FindOrCreateCustomBatchContext).
+func (sdr *Seedr) TryFindOrCreateCustom(traitName string, override Trait, opts ...ConflictOption) (TraitInstance, error) {
+	ins, err := sdr.FindOrCreateCustomBatchContext(context.Background(), traitName, 1, override, opts...)
+	if err != nil {
+		return TraitInstance{}, err
+	}
+	return ins.Index(0), nil
+}
+
+// TryFindOrCreateBatch finds or creates n instances of trait (see FindOrCreateCustomBatchContext).
+func (sdr *Seedr) TryFindOrCreateBatch(traitName string, n int, opts ...ConflictOption) (*TraitInstances, error) {
+	return sdr.FindOrCreateCustomBatchContext(context.Background(), traitName, n, nil, opts...)
+}
+
+// TryFindOrCreate finds or creates an instance of trait (see FindOrCreateCustomBatchContext).
+func (sdr *Seedr) TryFindOrCreate(traitName string, opts ...ConflictOption) (TraitInstance, error) {
+	return sdr.TryFindOrCreateCustom(traitName, nil, opts...)
+}
+
+// FindOrCreateCustomBatch is same as TryFindOrCreateCustomBatch, but it panics on error.
+func (sdr *Seedr) FindOrCreateCustomBatch(traitName string, n int, override Trait, opts ...ConflictOption) *TraitInstances {
+	ins, err := sdr.TryFindOrCreateCustomBatch(traitName, n, override, opts...)
+	panicOnError(err)
+	return ins
+}
+
+// FindOrCreateCustom is same as TryFindOrCreateCustom, but it panics on error.
+func (sdr *Seedr) FindOrCreateCustom(traitName string, override Trait, opts ...ConflictOption) TraitInstance {
+	ti, err := sdr.TryFindOrCreateCustom(traitName, override, opts...)
+	panicOnError(err)
+	return ti
+}
+
+// FindOrCreateBatch is same as TryFindOrCreateBatch, but it panics on error.
+func (sdr *Seedr) FindOrCreateBatch(traitName string, n int, opts ...ConflictOption) *TraitInstances {
+	ins, err := sdr.TryFindOrCreateBatch(traitName, n, opts...)
+	panicOnError(err)
+	return ins
+}
+
+// FindOrCreate is same as TryFindOrCreate, but it panics on error.
+func (sdr *Seedr) FindOrCreate(traitName string, opts ...ConflictOption) TraitInstance {
+	ti, err := sdr.TryFindOrCreate(traitName, opts...)
+	panicOnError(err)
+	return ti
+}
+
+// UpsertCustomBatchContext overrides values of trait definition
+// and upserts n instances of resulting trait (including related traits).
+// Records of factories with UniqueBy are created only if there is no record
+// with same values of UniqueBy fields, otherwise all other fields of existing
+// record (except PrimaryKey) are updated, or only ones given by UpdateFields option.
+// Child and many to many related records of existing records are not created.
+// Existing records are not tracked by Session, so Cleanup doesn't delete them.
+// "Create" driver must implement driver.Upserter (see SetCreateDriver).
+func (sdr *Seedr) UpsertCustomBatchContext(ctx context.Context, traitName string, n int, override Trait, opts ...ConflictOption) (*TraitInstances, error) {
+	return sdr.createConflict(ctx, driver.ConflictUpdate, traitName, n, override, opts)
+}
+
+// TryUpsertCustomBatch overrides values of trait definition
+// and upserts n instances of resulting trait (see UpsertCustomBatchContext).
+func (sdr *Seedr) TryUpsertCustomBatch(traitName string, n int, override Trait, opts ...ConflictOption) (*TraitInstances, error) {
+	return sdr.UpsertCustomBatchContext(context.Background(), traitName, n, override, opts...)
+}
+
+// TryUpsertCustom overrides values of trait definition
+// and upserts resulting trait (see UpsertCustomBatchContext).
+func (sdr *Seedr) TryUpsertCustom(traitName string, override Trait, opts ...ConflictOption) (TraitInstance, error) {
+	ins, err := sdr.UpsertCustomBatchContext(context.Background(), traitName, 1, override, opts...)
+	if err != nil {
+		return TraitInstance{}, err
+	}
+	return ins.Index(0), nil
+}
+
+// TryUpsertBatch upserts n instances of trait (see UpsertCustomBatchContext).
+func (sdr *Seedr) TryUpsertBatch(traitName string, n int, opts ...ConflictOption) (*TraitInstances, error) {
+	return sdr.UpsertCustomBatchContext(context.Background(), traitName, n, nil, opts...)
+}
+
+// TryUpsert upserts an instance of trait (see UpsertCustomBatchContext).
+func (sdr *Seedr) TryUpsert(traitName string, opts ...ConflictOption) (TraitInstance, error) {
+	return sdr.TryUpsertCustom(traitName, nil, opts...)
+}
+
+// UpsertCustomBatch is same as TryUpsertCustomBatch, but it panics on error.
+func (sdr *Seedr) UpsertCustomBatch(traitName string, n int, override Trait, opts ...ConflictOption) *TraitInstances {
+	ins, err := sdr.TryUpsertCustomBatch(traitName, n, override, opts...)
+	panicOnError(err)
+	return ins
+}
+
+// UpsertCustom is same as TryUpsertCustom, but it panics on error.
+func (sdr *Seedr) UpsertCustom(traitName string, override Trait, opts ...ConflictOption) TraitInstance {
+	ti, err := sdr.TryUpsertCustom(traitName, override, opts...)
+	panicOnError(err)
+	return ti
+}
+
+// UpsertBatch is same as TryUpsertBatch, but it panics on error.
+func (sdr *Seedr) UpsertBatch(traitName string, n int, opts ...ConflictOption) *TraitInstances {
+	ins, err := sdr.TryUpsertBatch(traitName, n, opts...)
+	panicOnError(err)
+	return ins
+}
+
+// Upsert is same as TryUpsert, but it panics on error.
+func (sdr *Seedr) Upsert(traitName string, opts ...ConflictOption) TraitInstance {
+	ti, err := sdr.TryUpsert(traitName, opts...)
+	panicOnError(err)
+	return ti
+}
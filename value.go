@@ -0,0 +1,119 @@
+package seedr
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// ValueEncoder encodes field values of types that are not supported natively
+// (e.g. structs, maps or slices) before they are passed to Driver,
+// and decodes them back on Scan.
+type ValueEncoder interface {
+	// Encode returns value of supported type (see Trait) that represents v.
+	Encode(v interface{}) (interface{}, error)
+	// Decode decodes src (as returned by Driver) into dest, that is a pointer.
+	Decode(src interface{}, dest interface{}) error
+}
+
+type jsonEncoder struct{}
+
+// JSONEncoder returns ValueEncoder that encodes values to JSON string.
+// Decode accepts both string and []byte.
+func JSONEncoder() ValueEncoder {
+	return jsonEncoder{}
+}
+
+func (jsonEncoder) Encode(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (jsonEncoder) Decode(src interface{}, dest interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), dest)
+	case []byte:
+		return json.Unmarshal(src, dest)
+	}
+	return fmt.Errorf("can't decode JSON from %T", src)
+}
+
+// valueEncoders is a registry of ValueEncoders by type and by kind.
+// It's never modified after registration, SetValueEncoder makes a copy.
+type valueEncoders struct {
+	types map[reflect.Type]ValueEncoder
+	kinds map[reflect.Kind]ValueEncoder
+}
+
+// SetValueEncoder registers ValueEncoder for all values of type of `sample`,
+// or, if `sample` is a reflect.Kind, for all values of that kind that are not supported natively.
+// Encoder registered for type takes precedence over native support of that type.
+// Example:
+//
+//	seedr.SetValueEncoder(map[string]interface{}{}, seedr.JSONEncoder())
+//	seedr.SetValueEncoder(reflect.Struct, seedr.JSONEncoder())
+func SetValueEncoder(sample interface{}, enc ValueEncoder) ConfigFunc {
+	if enc == nil {
+		panic("value encoder can't be nil")
+	}
+	if sample == nil {
+		panic("value encoder sample can't be nil")
+	}
+	return func(s *Seedr) {
+		encs := valueEncoders{
+			types: make(map[reflect.Type]ValueEncoder, len(s.encoders.types)+1),
+			kinds: make(map[reflect.Kind]ValueEncoder, len(s.encoders.kinds)+1),
+		}
+		for t, e := range s.encoders.types {
+			encs.types[t] = e
+		}
+		for k, e := range s.encoders.kinds {
+			encs.kinds[k] = e
+		}
+		if k, ok := sample.(reflect.Kind); ok {
+			encs.kinds[k] = enc
+		} else {
+			encs.types[reflect.TypeOf(sample)] = enc
+		}
+		s.encoders = encs
+	}
+}
+
+// lookup returns encoder for type t, or nil if values of t must not be encoded
+// (same rules as in getFieldValue).
+func (e valueEncoders) lookup(t reflect.Type) ValueEncoder {
+	if enc := e.types[t]; enc != nil {
+		return enc
+	}
+	if enc := e.kinds[t.Kind()]; enc != nil && !isNativeType(t) {
+		return enc
+	}
+	return nil
+}
+
+// isNativeType reports whether values of type t are supported without encoding.
+func isNativeType(t reflect.Type) bool {
+	_, err := getFieldValue(reflect.Zero(t).Interface(), valueEncoders{})
+	return err == nil
+}
+
+// isBasicKind reports whether t is of basic kind (e.g. named string or int type),
+// such values are passed to Driver without changes.
+func isBasicKind(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	}
+	return false
+}